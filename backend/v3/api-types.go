@@ -66,6 +66,20 @@ type Node struct {
 
 const matricsPath = "cgi.lua/status?type=system,ghn,ethernet,remote"
 
+const remotePath = "cgi.lua/status?type=remote"
+
+const systemPath = "cgi.lua/status?type=system"
+
+const configPath = "cgi.lua/config"
+
+type systemResponse struct {
+	System System `json:"system"`
+}
+
+type remoteResponse struct {
+	Remote map[string]Remote `json:"remote"`
+}
+
 type metricsResponse struct {
 	System System `json:"system"`
 	Ghn    struct {
@@ -202,38 +216,48 @@ type System struct {
 	} `json:"cpu"`
 }
 
+type EthernetClient struct {
+	Mac      string `json:"mac"`
+	Ipaddr   string `json:"ipaddr"`
+	Hostname string `json:"hostname"`
+	Port     int    `json:"port"`
+	Network  string `json:"network"`
+}
+
+type WirelessClient struct {
+	Mac      string `json:"mac"`
+	Per      int    `json:"per"`
+	Ssid     string `json:"ssid"`
+	Ipaddr   string `json:"ipaddr"`
+	Protocol string `json:"protocol"`
+	Radio    string `json:"radio"`
+	Bitrate  struct {
+		Tx int `json:"tx"`
+		Rx int `json:"rx"`
+	} `json:"bitrate"`
+	Band     int    `json:"band"`
+	Uptime   int    `json:"uptime"`
+	Hostname string `json:"hostname"`
+	Packets  struct {
+		Tx      int `json:"tx"`
+		RxError int `json:"rx_error"`
+		Rx      int `json:"rx"`
+		TxError int `json:"tx_error"`
+	} `json:"packets"`
+	Throughput struct {
+		Tx int `json:"tx"`
+		Rx int `json:"rx"`
+	} `json:"throughput"`
+	Network string `json:"network"`
+	Signal  int    `json:"signal"`
+}
+
 type Remote struct {
-	EthernetClients []any `json:"ethernet_clients"`
-	WirelessClients []struct {
-		Mac      string `json:"mac"`
-		Per      int    `json:"per"`
-		Ssid     string `json:"ssid"`
-		Ipaddr   string `json:"ipaddr"`
-		Protocol string `json:"protocol"`
-		Radio    string `json:"radio"`
-		Bitrate  struct {
-			Tx int `json:"tx"`
-			Rx int `json:"rx"`
-		} `json:"bitrate"`
-		Band     int    `json:"band"`
-		Uptime   int    `json:"uptime"`
-		Hostname string `json:"hostname"`
-		Packets  struct {
-			Tx      int `json:"tx"`
-			RxError int `json:"rx_error"`
-			Rx      int `json:"rx"`
-			TxError int `json:"tx_error"`
-		} `json:"packets"`
-		Throughput struct {
-			Tx int `json:"tx"`
-			Rx int `json:"rx"`
-		} `json:"throughput"`
-		Network string `json:"network"`
-		Signal  int    `json:"signal"`
-	} `json:"wireless_clients"`
-	Group    string `json:"group"`
-	Loadtime int    `json:"loadtime"`
-	Wireless []struct {
+	EthernetClients []EthernetClient `json:"ethernet_clients"`
+	WirelessClients []WirelessClient `json:"wireless_clients"`
+	Group           string           `json:"group"`
+	Loadtime        int              `json:"loadtime"`
+	Wireless        []struct {
 		Band         int      `json:"band"`
 		Clients      int      `json:"clients"`
 		Label        string   `json:"label"`
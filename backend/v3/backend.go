@@ -2,7 +2,6 @@ package v3
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 	"strings"
 
@@ -11,28 +10,30 @@ import (
 )
 
 func init() {
-	client.Register(New)
+	client.Register("v3", types.VersionRange{Min: "3", Max: "4"}, New)
 }
 
 type backend struct {
 	*client.Client
 }
 
+var _ types.Backend = (*backend)(nil)
+
 func New(ctx context.Context, c *client.Client) (types.Backend, error) {
 	b := backend{c}
 
 	req := loginRequest{Username: c.Username, Password: c.Password}
 	res := loginResponse{}
 	httpResponse, err := c.ApiRequestRaw(ctx, http.MethodPost, loginPath, &req, &res)
+	if err == nil && !res.Status {
+		err = &types.ErrLoginFailed{Message: res.Message}
+	}
 
+	c.CountLogin("v3", err)
 	if err != nil {
 		return nil, err
 	}
 
-	if !res.Status {
-		return nil, fmt.Errorf("login failed: %s", res.Message)
-	}
-
 	cookie := httpResponse.Header.Get("Set-Cookie")
 	if i := strings.Index(cookie, ";"); i > 0 {
 		cookie = cookie[:i]
@@ -41,3 +42,14 @@ func New(ctx context.Context, c *client.Client) (types.Backend, error) {
 	c.SetCookie(cookie)
 	return &b, nil
 }
+
+// Firmware returns the firmware version of the controller. It must not use
+// ApiRequest, as it is called during backend selection.
+func (b *backend) Firmware(ctx context.Context) (string, error) {
+	response := systemResponse{}
+	if _, err := b.ApiRequestRaw(ctx, http.MethodGet, systemPath, nil, &response); err != nil {
+		return "", err
+	}
+
+	return response.System.Version, nil
+}
@@ -0,0 +1,59 @@
+package v3
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/digineo/triax-eoc-exporter/types"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// collectWirelessClients emits per-client series for all wireless clients
+// accepted by the filter. Endpoints are visited in order of their MAC
+// address, so the same clients survive the limit across scrapes.
+func collectWirelessClients(ch chan<- prometheus.Metric, filter *types.ClientFilter, remotes map[string]Remote) {
+	const C, G = prometheus.CounterValue, prometheus.GaugeValue
+
+	metric := func(desc *prometheus.Desc, typ prometheus.ValueType, v float64, label ...string) {
+		ch <- prometheus.MustNewConstMetric(desc, typ, v, label...)
+	}
+
+	macs := make([]string, 0, len(remotes))
+	for mac := range remotes {
+		macs = append(macs, mac)
+	}
+	sort.Strings(macs)
+
+	exported, skipped := 0, 0
+	for _, mac := range macs {
+		node := remotes[mac]
+		for i := range node.WirelessClients {
+			client := &node.WirelessClients[i]
+			if !filter.Match(client.Mac) {
+				continue
+			}
+			if filter.Limit > 0 && exported >= filter.Limit {
+				skipped++
+				continue
+			}
+			exported++
+
+			label := []string{node.System.Name, client.Mac, client.Ssid, strconv.Itoa(client.Band)}
+			directed := func(desc *prometheus.Desc, typ prometheus.ValueType, rx, tx int) {
+				metric(desc, typ, float64(rx), append(label, "rx")...)
+				metric(desc, typ, float64(tx), append(label, "tx")...)
+			}
+
+			metric(types.WifiClientInfo, G, 1, append(label, client.Hostname, client.Ipaddr, client.Radio, client.Protocol)...)
+			metric(types.WifiClientSignal, G, float64(client.Signal), label...)
+			metric(types.WifiClientPer, G, float64(client.Per), label...)
+			metric(types.WifiClientUptime, G, float64(client.Uptime), label...)
+			directed(types.WifiClientBitrate, G, client.Bitrate.Rx, client.Bitrate.Tx)
+			directed(types.WifiClientThroughput, G, client.Throughput.Rx, client.Throughput.Tx)
+			directed(types.WifiClientPackets, C, client.Packets.Rx, client.Packets.Tx)
+			directed(types.WifiClientErrors, C, client.Packets.RxError, client.Packets.TxError)
+		}
+	}
+
+	metric(types.CtrlWirelessClientsSkipped, G, float64(skipped))
+}
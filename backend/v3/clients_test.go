@@ -0,0 +1,110 @@
+package v3
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/digineo/triax-eoc-exporter/types"
+	"github.com/digineo/triax-eoc-exporter/types/typestest"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testRemotes builds two endpoints with three wireless clients each
+func testRemotes() map[string]Remote {
+	remotes := make(map[string]Remote)
+	for i := 1; i <= 2; i++ {
+		node := Remote{}
+		node.System.Name = fmt.Sprintf("apartment-%d", i)
+		for j := 1; j <= 3; j++ {
+			node.WirelessClients = append(node.WirelessClients, WirelessClient{
+				Mac:  fmt.Sprintf("aa:bb:cc:00:%02d:%02d", i, j),
+				Ssid: "wifi",
+				Band: 5,
+			})
+		}
+		remotes[fmt.Sprintf("00:00:00:00:00:%02d", i)] = node
+	}
+	return remotes
+}
+
+// collectClients returns the exported client MACs and all metrics
+func collectClients(t *testing.T, filter *types.ClientFilter) ([]string, []prometheus.Metric) {
+	ch := make(chan prometheus.Metric)
+	go func() {
+		collectWirelessClients(ch, filter, testRemotes())
+		close(ch)
+	}()
+
+	var macs []string
+	var metrics []prometheus.Metric
+	for m := range ch {
+		metrics = append(metrics, m)
+		if m.Desc() != types.WifiClientInfo {
+			continue
+		}
+
+		pb := dto.Metric{}
+		require.NoError(t, m.Write(&pb))
+		for _, label := range pb.Label {
+			if label.GetName() == "client_mac" {
+				macs = append(macs, label.GetValue())
+			}
+		}
+	}
+
+	return macs, metrics
+}
+
+func TestWirelessClientsFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  types.ClientFilter
+		macs    []string
+		skipped float64
+	}{
+		{
+			name:   "unrestricted",
+			filter: types.ClientFilter{},
+			macs: []string{
+				"aa:bb:cc:00:01:01", "aa:bb:cc:00:01:02", "aa:bb:cc:00:01:03",
+				"aa:bb:cc:00:02:01", "aa:bb:cc:00:02:02", "aa:bb:cc:00:02:03",
+			},
+		},
+		{
+			name:   "allowlist",
+			filter: types.ClientFilter{Macs: []string{"AA:BB:CC:00:02:02"}},
+			macs:   []string{"aa:bb:cc:00:02:02"},
+		},
+		{
+			name:   "regex",
+			filter: types.ClientFilter{MacRegex: regexp.MustCompile(":01:0[12]$")},
+			macs:   []string{"aa:bb:cc:00:01:01", "aa:bb:cc:00:01:02"},
+		},
+		{
+			// endpoints are visited in order of their MAC address
+			name:    "limit",
+			filter:  types.ClientFilter{Limit: 4},
+			macs:    []string{"aa:bb:cc:00:01:01", "aa:bb:cc:00:01:02", "aa:bb:cc:00:01:03", "aa:bb:cc:00:02:01"},
+			skipped: 2,
+		},
+		{
+			// only matching clients count as skipped
+			name:    "limit and regex",
+			filter:  types.ClientFilter{Limit: 1, MacRegex: regexp.MustCompile(":0[12]:01$")},
+			macs:    []string{"aa:bb:cc:00:01:01"},
+			skipped: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			macs, metrics := collectClients(t, &tt.filter)
+			assert.Equal(t, tt.macs, macs)
+			assert.Equal(t, tt.skipped, typestest.Value(t, metrics, types.CtrlWirelessClientsSkipped))
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/digineo/triax-eoc-exporter/types"
 	"github.com/prometheus/client_golang/prometheus"
@@ -26,6 +27,53 @@ func (b *backend) Collect(ctx context.Context, ch chan<- prometheus.Metric) erro
 		metric(types.CounterErrors, C, float64(counters.TxErr), node, ifname, "tx")
 	}
 
+	modemMetrics := func(modem *Modem, port string) {
+		metric(types.CtrlGhnInfo, G, 1, port, modem.Name, modem.Firmware, modem.Mac, modem.DomainID, modem.DomainName, modem.DomainMode, modem.Master)
+		metric(types.CtrlGhnResetCause, G, 1, port, modem.ResetCause)
+		metric(types.CtrlGhnCPUUsage, G, float64(modem.CPUUsage), port)
+		metric(types.CtrlGhnMemUsage, G, float64(modem.MemUsage), port)
+		metric(types.CtrlGhnUptime, G, float64(modem.Uptime), port)
+		metric(types.CtrlGhnSpeed, G, modem.Speed, port)
+		metric(types.CtrlGhnNoiseMin, G, modem.Noise.Min, port)
+		metric(types.CtrlGhnNoiseAvg, G, modem.Noise.Avg, port)
+		metric(types.CtrlGhnNoiseMax, G, modem.Noise.Max, port)
+		metric(types.CtrlGhnNoiseAgc, G, float64(modem.Noise.Agc), port)
+		metric(types.CtrlGhnBytes, C, float64(modem.RxBytes), port, "rx")
+		metric(types.CtrlGhnBytes, C, float64(modem.TxBytes), port, "tx")
+		metric(types.CtrlGhnPackets, C, float64(modem.RxPackets), port, "rx")
+		metric(types.CtrlGhnPackets, C, float64(modem.TxPackets), port, "tx")
+		metric(types.CtrlGhnErrors, C, float64(modem.RxErrors), port, "rx")
+		metric(types.CtrlGhnErrors, C, float64(modem.TxErrors), port, "tx")
+		metric(types.CtrlGhnDrops, C, float64(modem.RxDrops), port, "rx")
+		metric(types.CtrlGhnDrops, C, float64(modem.TxDrops), port, "tx")
+		metric(types.CtrlGhnBlocks, C, float64(modem.RxBlocks), port, "rx")
+		metric(types.CtrlGhnBlocks, C, float64(modem.TxBlocks), port, "tx")
+		metric(types.CtrlGhnTxBlocksResent, C, float64(modem.TxBlocksResent), port)
+		metric(types.CtrlGhnRxBlocksError, C, float64(modem.RxBlocksError), port)
+		metric(types.CtrlGhnLinkLost, C, float64(modem.LinkLost), port)
+		metric(types.CtrlGhnRetxPercent, G, modem.RetxPercent, port)
+		metric(types.CtrlGhnFecPercent, G, float64(modem.FecPercent), port)
+	}
+
+	ethernetMetrics := func(port *EthernetPort) {
+		label := []string{strconv.Itoa(port.Index), port.Switch, port.Access}
+		directed := func(desc *prometheus.Desc, typ prometheus.ValueType, rx, tx int) {
+			metric(desc, typ, float64(rx), append(label, "rx")...)
+			metric(desc, typ, float64(tx), append(label, "tx")...)
+		}
+
+		metric(types.CtrlEthLink, G, boolToFloat(port.Link), label...)
+		metric(types.CtrlEthAutoneg, G, boolToFloat(port.Autoneg), label...)
+		directed(types.CtrlEthRate, G, port.RxRate, port.TxRate)
+		directed(types.CtrlEthBytes, C, port.RxBytes, port.TxBytes)
+		directed(types.CtrlEthPackets, C, port.RxPackets, port.TxPackets)
+		directed(types.CtrlEthErrors, C, port.RxErrors, port.TxErrors)
+		directed(types.CtrlEthDrops, C, port.RxDrops, port.TxDrops)
+		directed(types.CtrlEthUnicast, C, port.RxUnicast, port.TxUnicast)
+		directed(types.CtrlEthMulticast, C, port.RxMulticast, port.TxMulticast)
+		directed(types.CtrlEthBroadcast, C, port.RxBroadcast, port.TxBroadcast)
+	}
+
 	if err := b.Get(ctx, capabilitiesPath, &capabilities); err != nil {
 		return err
 	}
@@ -33,21 +81,49 @@ func (b *backend) Collect(ctx context.Context, ch chan<- prometheus.Metric) erro
 	if err := b.Get(ctx, matricsPath, &response); err != nil {
 		return err
 	}
+	fetched := time.Now()
 
-	metric(types.CtrlInfo, C, 1, capabilities.Product.Serial, capabilities.Product.Mac, response.System.Version)
+	metric(types.CtrlInfo, G, 1, capabilities.Product.Serial, capabilities.Product.Mac, response.System.Version)
 	metric(types.CtrlUptime, C, float64(response.System.Uptime))
+
+	product := &capabilities.Product
+	metric(types.CtrlHardwareInfo, G, 1,
+		strconv.Itoa(product.BoardNo), product.BoardRev, product.BoardID, product.Type, product.Model, product.Name,
+		strconv.Itoa(product.BoardYear), strconv.Itoa(product.BoardWeek),
+	)
+	for iface, mac := range product.Macs {
+		metric(types.CtrlMacInfo, G, 1, iface, mac)
+	}
+	metric(types.CtrlFeature, G, boolToFloat(capabilities.Features.GhnMux), "ghn-mux")
+	metric(types.CtrlFeature, G, boolToFloat(capabilities.Features.Wifi), "wifi")
 	metric(types.CtrlMemoryTotal, G, float64(response.System.Memory.Total))
 	metric(types.CtrlMemoryFree, G, float64(response.System.Memory.Total-response.System.Memory.Used))
+	metric(types.CtrlMemoryUsage, G, response.System.Memory.Usage)
+	metric(types.CtrlLoad, G, response.System.CPU.Usage)
+	metric(types.CtrlCPUUsage, G, response.System.CPU.Usage1Min, "1m")
+	metric(types.CtrlCPUUsage, G, response.System.CPU.Usage5Min, "5m")
+	metric(types.CtrlCPUUsage, G, response.System.CPU.Usage15Min, "15m")
+	metric(types.CtrlProcesses, G, float64(response.System.Processes))
+	metric(types.CtrlImagesValid, G, boolToFloat(response.System.ImagesValid))
+	metric(types.CtrlClockOffset, G, float64(int64(response.System.Timestamp)-fetched.Unix()))
 
 	for _, modem := range response.Ghn.Modems {
 		number := strconv.Itoa(modem.Index + 1)
 		metric(types.CtrlGhnNumRegistered, G, float64(modem.EndpointRegistered), number)
 		metric(types.CtrlGhnNumOnline, G, float64(modem.EndpointCount), number)
+		modemMetrics(&modem, number)
+	}
+
+	for _, port := range response.Ethernet.Ports {
+		ethernetMetrics(&port)
 	}
 
 	// mapping from MAC addresses to names
 	macToName := make(map[string]string)
 
+	// config hashes of all endpoints
+	configHashes := make([]string, 0, len(response.Remote))
+
 	// Endpoint side
 	for mac, node := range response.Remote {
 		name := node.System.Name
@@ -57,40 +133,117 @@ func (b *backend) Collect(ctx context.Context, ch chan<- prometheus.Metric) erro
 
 		metric(types.NodeInfo, G, 1, name, node.Serial, node.Mac, node.System.Model)
 		metric(types.NodeStatus, G, float64(node.State), name)
+		metric(types.NodeStatusInfo, G, 1, name, node.Status, node.Message)
+		metric(types.NodeConfigInfo, G, 1, name, node.ConfigHash)
+		configHashes = append(configHashes, node.ConfigHash)
+		for _, state := range types.EndpointStates {
+			metric(types.NodeState, G, boolToFloat(node.State == state.Value), name, state.Name)
+		}
+		if types.IsOffline(node.State) {
+			since := node.Seen
+			if since == 0 {
+				since = node.Timestamp
+			}
+			metric(types.NodeOffline, G, float64(since), name)
+		}
+
+		sys := &node.System
+		metric(types.NodeSoftwareInfo, G, 1, name, sys.SwVersion, sys.GhnVersion, sys.Hostname, sys.Model, sys.Description, sys.HostMac)
+
+		network := &node.Network
+		metric(types.NodeNetworkInfo, G, 1, name,
+			network.Ipv4.Address, strconv.Itoa(network.Ipv4.Prefix),
+			network.Ipv6.Address, strconv.Itoa(network.Ipv6.Prefix),
+		)
+		for _, area := range network.Areas {
+			metric(types.NodeAreaInfo, G, 1, name, area.Network, area.Ipv4.Address, strconv.Itoa(area.Ipv4.Prefix))
+		}
 
 		if uptime := node.System.Uptime; uptime != nil {
 			metric(types.NodeUptime, G, float64(*uptime), name)
 		}
 
 		// ethernet statistics
+		ethClients := make(map[int]int)
 		for _, stats := range node.Ethernet {
+			ethClients[stats.Port] = 0
 			if stats.Link {
 				counterMetric(&stats.Counters, name, fmt.Sprintf("eth%d", stats.Port))
 			}
 		}
+		for _, client := range node.EthernetClients {
+			ethClients[client.Port]++
+		}
+		for port, n := range ethClients {
+			metric(types.NodeEthernetClients, G, float64(n), name, fmt.Sprintf("eth%d", port))
+		}
 
 		// wireless statistics
 		for _, stats := range node.Wireless {
-			metric(types.NodeClients, G, float64(stats.Clients), name, strconv.Itoa(stats.Band))
+			band := strconv.Itoa(stats.Band)
+			metric(types.NodeClients, G, float64(stats.Clients), name, band)
+			metric(types.WifiInfo, G, 1, name, band, stats.Radio, stats.Label, stats.Mac, stats.ChannelWidth)
+			metric(types.WifiEnabled, G, boolToFloat(stats.Enabled), name, band)
+			metric(types.WifiChannel, G, float64(stats.Channel), name, band)
+			metric(types.WifiFrequency, G, float64(stats.Frequency), name, band)
+			metric(types.WifiTxPower, G, float64(stats.Txpower), name, band)
+			metric(types.WifiBitrate, G, float64(stats.Bitrate), name, band)
 			counterMetric(&stats.Counters, name, fmt.Sprintf("wifi%d", stats.Band))
 		}
 
 		// G.hn statistics
-		if len(node.Ghn) > 0 && node.Ghn[0].Status != nil {
-			ghn := node.Ghn[0]
+		for i := range node.Ghn {
+			ghn := &node.Ghn[i]
+			if ghn.Status == nil {
+				continue
+			}
+
+			port := strconv.Itoa(ghn.Port)
+			label := []string{name, port, ghn.Label}
+			link := []string{name, types.SIDE_ENDPOINT, port, ghn.Label}
+
+			metric(types.NodeGhnPort, G, float64(ghn.Port), name, ghn.Mac)
+			metric(types.GhnStatus, G, boolToFloat(*ghn.Status), label...)
+			metric(types.GhnResetCause, G, 1, append(label, ghn.ResetCause)...)
+			metric(types.GhnRetxPercent, G, float64(ghn.RetxPercent), label...)
+			metric(types.GhnFecPercent, G, float64(ghn.FecPercent), label...)
+			metric(types.GhnSpeed, G, ghn.Speed, label...)
+			metric(types.GhnCPUUsage, G, float64(ghn.CPUUsage), label...)
+			metric(types.GhnMemUsage, G, float64(ghn.MemUsage), label...)
+			metric(types.GhnUptime, G, float64(ghn.Uptime), label...)
+			metric(types.GhnClients, G, float64(ghn.Clients), label...)
+			counterMetric(&ghn.Counters, name, "ghn"+port)
+
 			if ghn.Bitrate != nil {
-				metric(types.GhnRxbps, G, float64(ghn.Bitrate.Rx), name)
-				metric(types.GhnTxbps, G, float64(ghn.Bitrate.Tx), name)
+				metric(types.GhnRxbps, G, float64(ghn.Bitrate.Rx), label...)
+				metric(types.GhnTxbps, G, float64(ghn.Bitrate.Tx), label...)
 			}
 			if ghn.Snr != nil {
-				metric(types.GhnSnrMin, G, float64(ghn.Snr.Min), name, types.SIDE_ENDPOINT)
-				metric(types.GhnSnrAvg, G, float64(ghn.Snr.Avg), name, types.SIDE_ENDPOINT)
-				metric(types.GhnSnrMax, G, float64(ghn.Snr.Max), name, types.SIDE_ENDPOINT)
-
+				metric(types.GhnSnrMin, G, ghn.Snr.Min, link...)
+				metric(types.GhnSnrAvg, G, ghn.Snr.Avg, link...)
+				metric(types.GhnSnrMax, G, ghn.Snr.Max, link...)
+			}
+			if ghn.Power != nil {
+				metric(types.GhnPowerMin, G, ghn.Power.Min, link...)
+				metric(types.GhnPowerAvg, G, ghn.Power.Avg, link...)
+				metric(types.GhnPowerMax, G, ghn.Power.Max, link...)
+				metric(types.GhnPowerAgc, G, float64(ghn.Power.Agc), link...)
+			}
+			if ghn.Noise != nil {
+				metric(types.GhnNoiseMin, G, ghn.Noise.Min, label...)
+				metric(types.GhnNoiseAvg, G, ghn.Noise.Avg, label...)
+				metric(types.GhnNoiseMax, G, ghn.Noise.Max, label...)
+				metric(types.GhnNoiseAgc, G, float64(ghn.Noise.Agc), label...)
 			}
 		}
 	}
 
+	metric(types.CtrlConfigDrift, G, float64(types.ConfigDrift(configHashes, b.ExpectedConfigHash)))
+
+	if filter := b.WirelessClients; filter != nil {
+		collectWirelessClients(ch, filter, response.Remote)
+	}
+
 	// Controller Side
 	for mac, node := range response.Ghn.Nodes {
 		name := macToName[mac]
@@ -98,11 +251,36 @@ func (b *backend) Collect(ctx context.Context, ch chan<- prometheus.Metric) erro
 			name = mac
 		}
 
-		metric(types.GhnWireLength, G, float64(node.WireLength), name)
-		metric(types.GhnSnrMin, G, float64(node.Snr.Min), name, types.SIDE_CONTROLLER)
-		metric(types.GhnSnrAvg, G, float64(node.Snr.Avg), name, types.SIDE_CONTROLLER)
-		metric(types.GhnSnrMax, G, float64(node.Snr.Max), name, types.SIDE_CONTROLLER)
+		// the G.hn modem of the controller serving the endpoint
+		port, label := node.Modem, node.Modem
+		if modem, ok := response.Ghn.Modems[node.Modem]; ok {
+			port, label = strconv.Itoa(modem.Index+1), modem.Name
+		}
+		link := []string{name, types.SIDE_CONTROLLER, port, label}
+
+		metric(types.GhnWireLength, G, float64(node.WireLength), link...)
+		metric(types.GhnSnrMin, G, node.Snr.Min, link...)
+		metric(types.GhnSnrAvg, G, node.Snr.Avg, link...)
+		metric(types.GhnSnrMax, G, node.Snr.Max, link...)
+		metric(types.GhnPowerMin, G, node.Power.Min, link...)
+		metric(types.GhnPowerAvg, G, node.Power.Avg, link...)
+		metric(types.GhnPowerMax, G, node.Power.Max, link...)
+		metric(types.GhnPowerAgc, G, float64(node.Power.Agc), link...)
+		metric(types.GhnRxErrorPercent, G, node.RxErrorPercent, link...)
+		metric(types.GhnRxAbortPercent, G, node.RxAbortPercent, link...)
+		metric(types.GhnPhyRate, G, node.RxRate, append(link, "rx")...)
+		metric(types.GhnPhyRate, G, node.TxRate, append(link, "tx")...)
+		metric(types.GhnRxFrames, C, float64(node.RxFrames), link...)
+		metric(types.GhnRxLPDUs, C, float64(node.RxLPDUs), link...)
 	}
 
 	return nil
 }
+
+func boolToFloat(val bool) float64 {
+	if val {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,303 @@
+package v3
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/digineo/triax-eoc-exporter/client"
+	"github.com/digineo/triax-eoc-exporter/types"
+	"github.com/digineo/triax-eoc-exporter/types/typestest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestBackend starts a fake controller serving the fixtures in testdata
+// and logs into it.
+func newTestBackend(t *testing.T, opts client.Options) *backend {
+	t.Helper()
+
+	fixtures := map[string]string{
+		"/" + loginPath:        "login.json",
+		"/" + capabilitiesPath: "capabilities.json",
+		"/cgi.lua/status":      "status.json",
+		"/" + configPath:       "config.json",
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, ok := fixtures[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		data, err := os.ReadFile(filepath.Join("testdata", file))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if r.URL.Path == "/"+loginPath {
+			w.Header().Set("Set-Cookie", "sid=secret; Path=/")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	}))
+	t.Cleanup(srv.Close)
+
+	endpoint, _ := url.Parse(srv.URL)
+	endpoint.User = url.UserPassword("admin", "secret")
+
+	return loginTestBackend(t, endpoint, opts)
+}
+
+// loginTestBackend logs into the controller at the given endpoint.
+func loginTestBackend(t *testing.T, endpoint *url.URL, opts client.Options) *backend {
+	t.Helper()
+
+	c, err := client.NewClient(endpoint, opts)
+	require.NoError(t, err)
+	c.WirelessClients = &types.ClientFilter{}
+
+	b, err := New(context.Background(), c)
+	require.NoError(t, err)
+
+	return b.(*backend)
+}
+
+func TestCollectCatalog(t *testing.T) {
+	b := newTestBackend(t, client.Options{})
+	metrics := typestest.Collect(t, b)
+
+	typestest.CheckCatalog(t, metrics,
+		types.CollectorController,
+		types.CollectorEndpoint,
+		types.CollectorWirelessClients,
+	)
+}
+
+func TestControllerHardware(t *testing.T) {
+	b := newTestBackend(t, client.Options{})
+	metrics := typestest.Collect(t, b)
+
+	assert.Equal(t, 1.0, typestest.Value(t, metrics, types.CtrlHardwareInfo, "4711", "B", "ECU-16", "controller", "GCU 300", "EoC Controller", "2021", "12"))
+	assert.Equal(t, 1.0, typestest.Value(t, metrics, types.CtrlMacInfo, "eth0", "00:11:22:33:44:00"))
+	assert.Equal(t, 1.0, typestest.Value(t, metrics, types.CtrlMacInfo, "ghn0", "00:11:22:33:44:01"))
+	assert.Equal(t, 1.0, typestest.Value(t, metrics, types.CtrlFeature, "ghn-mux"))
+	assert.Equal(t, 0.0, typestest.Value(t, metrics, types.CtrlFeature, "wifi"))
+}
+
+func TestControllerHealth(t *testing.T) {
+	b := newTestBackend(t, client.Options{})
+	metrics := typestest.Collect(t, b)
+
+	assert.Equal(t, 40.5, typestest.Value(t, metrics, types.CtrlMemoryUsage))
+	assert.Equal(t, 10.0, typestest.Value(t, metrics, types.CtrlLoad))
+	assert.Equal(t, 9.5, typestest.Value(t, metrics, types.CtrlCPUUsage, "1m"))
+	assert.Equal(t, 12.5, typestest.Value(t, metrics, types.CtrlCPUUsage, "5m"))
+	assert.Equal(t, 11.0, typestest.Value(t, metrics, types.CtrlCPUUsage, "15m"))
+	assert.Equal(t, 120.0, typestest.Value(t, metrics, types.CtrlProcesses))
+	assert.Equal(t, 1.0, typestest.Value(t, metrics, types.CtrlImagesValid))
+
+	// the fixture's clock lags behind, so the offset is negative
+	offset := float64(1704110400 - time.Now().Unix())
+	assert.InDelta(t, offset, typestest.Value(t, metrics, types.CtrlClockOffset), 60)
+}
+
+func TestControllerGhn(t *testing.T) {
+	b := newTestBackend(t, client.Options{})
+	metrics := typestest.Collect(t, b)
+
+	assert.Equal(t, 1.0, typestest.Value(t, metrics, types.CtrlGhnInfo, "1", "G.hn 1", "1.2.3", "00:11:22:33:44:01", "1", "domain1", "auto", "00:11:22:33:44:01"))
+	assert.Equal(t, 1.0, typestest.Value(t, metrics, types.CtrlGhnResetCause, "1", "power-on"))
+	assert.Equal(t, 15.0, typestest.Value(t, metrics, types.CtrlGhnCPUUsage, "1"))
+	assert.Equal(t, 40.0, typestest.Value(t, metrics, types.CtrlGhnMemUsage, "1"))
+	assert.Equal(t, -120.5, typestest.Value(t, metrics, types.CtrlGhnNoiseMin, "1"))
+	assert.Equal(t, 123456789.0, typestest.Value(t, metrics, types.CtrlGhnBytes, "1", "rx"))
+	assert.Equal(t, 987654321.0, typestest.Value(t, metrics, types.CtrlGhnBytes, "1", "tx"))
+	assert.Equal(t, 3.0, typestest.Value(t, metrics, types.CtrlGhnErrors, "1", "rx"))
+	assert.Equal(t, 4.0, typestest.Value(t, metrics, types.CtrlGhnErrors, "1", "tx"))
+	assert.Equal(t, 30.0, typestest.Value(t, metrics, types.CtrlGhnTxBlocksResent, "1"))
+	assert.Equal(t, 7.0, typestest.Value(t, metrics, types.CtrlGhnRxBlocksError, "1"))
+	assert.Equal(t, 1.5, typestest.Value(t, metrics, types.CtrlGhnRetxPercent, "1"))
+	assert.Equal(t, 0.5, typestest.Value(t, metrics, types.CtrlGhnFecPercent, "1"))
+}
+
+func TestControllerEthernet(t *testing.T) {
+	b := newTestBackend(t, client.Options{})
+	metrics := typestest.Collect(t, b)
+
+	port := []string{"1", "sw0", "uplink"}
+	assert.Equal(t, 1.0, typestest.Value(t, metrics, types.CtrlEthLink, port...))
+	assert.Equal(t, 1.0, typestest.Value(t, metrics, types.CtrlEthAutoneg, port...))
+	assert.Equal(t, 100.0, typestest.Value(t, metrics, types.CtrlEthRate, append(port, "rx")...))
+	assert.Equal(t, 200.0, typestest.Value(t, metrics, types.CtrlEthRate, append(port, "tx")...))
+	assert.Equal(t, 1000000.0, typestest.Value(t, metrics, types.CtrlEthBytes, append(port, "rx")...))
+	assert.Equal(t, 2000000.0, typestest.Value(t, metrics, types.CtrlEthBytes, append(port, "tx")...))
+	assert.Equal(t, 2.0, typestest.Value(t, metrics, types.CtrlEthDrops, append(port, "rx")...))
+	assert.Equal(t, 7.0, typestest.Value(t, metrics, types.CtrlEthDrops, append(port, "tx")...))
+	assert.Equal(t, 4995.0, typestest.Value(t, metrics, types.CtrlEthUnicast, append(port, "rx")...))
+	assert.Equal(t, 9.0, typestest.Value(t, metrics, types.CtrlEthMulticast, append(port, "tx")...))
+	assert.Equal(t, 1.0, typestest.Value(t, metrics, types.CtrlEthBroadcast, append(port, "rx")...))
+}
+
+func TestEndpoints(t *testing.T) {
+	b := newTestBackend(t, client.Options{})
+
+	endpoints, err := b.Endpoints(context.Background())
+	require.NoError(t, err)
+	require.Len(t, endpoints, 4)
+
+	assert.Equal(t, "apartment-1", endpoints[0].Name)
+	assert.Equal(t, []types.EthernetClient{{
+		Interface: "eth1",
+		Mac:       "22:33:44:55:66:77",
+		Ipaddr:    "192.168.1.101",
+		Hostname:  "tv",
+	}, {
+		Interface: "eth1",
+		Mac:       "22:33:44:55:66:78",
+		Ipaddr:    "192.168.1.102",
+		Hostname:  "laptop",
+	}}, endpoints[0].EthernetClients)
+
+	assert.Equal(t, "apartment-2", endpoints[1].Name)
+	assert.Empty(t, endpoints[1].EthernetClients)
+}
+
+func TestEthernetClients(t *testing.T) {
+	b := newTestBackend(t, client.Options{})
+	metrics := typestest.Collect(t, b)
+
+	assert.Equal(t, 2.0, typestest.Value(t, metrics, types.NodeEthernetClients, "apartment-1", "eth1"))
+	assert.Equal(t, 0.0, typestest.Value(t, metrics, types.NodeEthernetClients, "apartment-1", "eth2"))
+}
+
+func TestWirelessRadio(t *testing.T) {
+	b := newTestBackend(t, client.Options{})
+	metrics := typestest.Collect(t, b)
+
+	assert.Equal(t, 1.0, typestest.Value(t, metrics, types.WifiInfo, "apartment-1", "5", "radio1", "5 GHz", "aa:bb:cc:00:01:05", "80"))
+	assert.Equal(t, 1.0, typestest.Value(t, metrics, types.WifiEnabled, "apartment-1", "5"))
+	assert.Equal(t, 36.0, typestest.Value(t, metrics, types.WifiChannel, "apartment-1", "5"))
+	assert.Equal(t, 5180.0, typestest.Value(t, metrics, types.WifiFrequency, "apartment-1", "5"))
+	assert.Equal(t, 20.0, typestest.Value(t, metrics, types.WifiTxPower, "apartment-1", "5"))
+	assert.Equal(t, 866.0, typestest.Value(t, metrics, types.WifiBitrate, "apartment-1", "5"))
+}
+
+func TestEndpointState(t *testing.T) {
+	b := newTestBackend(t, client.Options{})
+	metrics := typestest.Collect(t, b)
+
+	tests := []struct {
+		name         string
+		state        string
+		offlineSince float64
+	}{
+		{"apartment-1", "ok", 0},
+		{"apartment-2", "offline", 1704000000},
+		{"apartment-3", "offline_responding", 1704050000},
+		{"apartment-4", "offline_detected", 1704100000}, // falls back to the timestamp
+	}
+
+	for _, tt := range tests {
+		for _, state := range types.EndpointStates {
+			want := 0.0
+			if state.Name == tt.state {
+				want = 1
+			}
+			assert.Equal(t, want, typestest.Value(t, metrics, types.NodeState, tt.name, state.Name), "%s %s", tt.name, state.Name)
+		}
+
+		if tt.offlineSince != 0 {
+			assert.Equal(t, tt.offlineSince, typestest.Value(t, metrics, types.NodeOffline, tt.name), tt.name)
+		}
+	}
+
+	// online endpoints have no offline_since
+	offline := 0
+	for _, m := range metrics {
+		if m.Desc() == types.NodeOffline {
+			offline++
+		}
+	}
+	assert.Equal(t, 3, offline)
+}
+
+func TestGhnLink(t *testing.T) {
+	b := newTestBackend(t, client.Options{})
+	metrics := typestest.Collect(t, b)
+
+	// both sides of the link are labelled by the G.hn interface measuring it
+	endpoint := []string{"apartment-1", types.SIDE_ENDPOINT, "1", "G.hn"}
+	controller := []string{"apartment-1", types.SIDE_CONTROLLER, "1", "G.hn 1"}
+
+	assert.Equal(t, 21.0, typestest.Value(t, metrics, types.GhnSnrMin, endpoint...))
+	assert.Equal(t, -9.0, typestest.Value(t, metrics, types.GhnPowerAvg, endpoint...))
+	assert.Equal(t, 40.5, typestest.Value(t, metrics, types.GhnSnrMax, controller...))
+	assert.Equal(t, -10.5, typestest.Value(t, metrics, types.GhnPowerMin, controller...))
+	assert.Equal(t, 42.0, typestest.Value(t, metrics, types.GhnWireLength, controller...))
+	assert.Equal(t, 0.5, typestest.Value(t, metrics, types.GhnRxErrorPercent, controller...))
+	assert.Equal(t, 750.25, typestest.Value(t, metrics, types.GhnPhyRate, append(controller, "rx")...))
+	assert.Equal(t, 800.5, typestest.Value(t, metrics, types.GhnPhyRate, append(controller, "tx")...))
+	assert.Equal(t, 654321.0, typestest.Value(t, metrics, types.GhnRxLPDUs, controller...))
+}
+
+func TestReplay(t *testing.T) {
+	dir := t.TempDir()
+
+	// record a scrape of the fake controller
+	recorded := newTestBackend(t, client.Options{RecordDir: dir, RecordRedact: true})
+	typestest.Collect(t, recorded)
+
+	// replay it without any controller
+	endpoint, _ := url.Parse("http://replay.invalid")
+	endpoint.User = url.UserPassword("admin", "secret")
+
+	b := loginTestBackend(t, endpoint, client.Options{ReplayDir: dir})
+	metrics := typestest.Collect(t, b)
+
+	typestest.CheckCatalog(t, metrics,
+		types.CollectorController,
+		types.CollectorEndpoint,
+		types.CollectorWirelessClients,
+	)
+}
+
+func TestFirmware(t *testing.T) {
+	b := newTestBackend(t, client.Options{})
+
+	firmware, err := b.Firmware(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "3.4.7", firmware)
+}
+
+func TestConfig(t *testing.T) {
+	b := newTestBackend(t, client.Options{})
+
+	config, err := b.GetConfig(context.Background())
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"system": {"name": "controller"}}`, string(config))
+
+	assert.NoError(t, b.SetConfig(context.Background(), config))
+}
+
+func TestSystemInfo(t *testing.T) {
+	b := newTestBackend(t, client.Options{})
+
+	info, err := b.SystemInfo(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, &types.SystemInfo{
+		Serial:   "S123456",
+		Mac:      "00:11:22:33:44:00",
+		Model:    "GCU 300",
+		Name:     "controller",
+		Firmware: "3.4.7",
+		Uptime:   86400,
+	}, info)
+}
@@ -0,0 +1,18 @@
+package v3
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+func (b *backend) GetConfig(ctx context.Context) (json.RawMessage, error) {
+	config := json.RawMessage{}
+	err := b.Get(ctx, configPath, &config)
+	return config, err
+}
+
+func (b *backend) SetConfig(ctx context.Context, config json.RawMessage) error {
+	res := json.RawMessage{}
+	return b.ApiRequest(ctx, http.MethodPost, configPath, config, &res)
+}
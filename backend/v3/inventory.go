@@ -0,0 +1,45 @@
+package v3
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/digineo/triax-eoc-exporter/types"
+)
+
+func (b *backend) Endpoints(ctx context.Context) ([]types.Endpoint, error) {
+	response := remoteResponse{}
+	if err := b.Get(ctx, remotePath, &response); err != nil {
+		return nil, err
+	}
+
+	endpoints := make([]types.Endpoint, 0, len(response.Remote))
+	for mac, node := range response.Remote {
+		endpoint := types.Endpoint{
+			Name:            node.System.Name,
+			Mac:             mac,
+			Serial:          node.Serial,
+			Model:           node.System.Model,
+			State:           node.State,
+			EthernetClients: make([]types.EthernetClient, 0, len(node.EthernetClients)),
+		}
+
+		for _, client := range node.EthernetClients {
+			endpoint.EthernetClients = append(endpoint.EthernetClients, types.EthernetClient{
+				Interface: fmt.Sprintf("eth%d", client.Port),
+				Mac:       client.Mac,
+				Ipaddr:    client.Ipaddr,
+				Hostname:  client.Hostname,
+			})
+		}
+
+		endpoints = append(endpoints, endpoint)
+	}
+
+	sort.Slice(endpoints, func(i, j int) bool {
+		return endpoints[i].Mac < endpoints[j].Mac
+	})
+
+	return endpoints, nil
+}
@@ -0,0 +1,28 @@
+package v3
+
+import (
+	"context"
+
+	"github.com/digineo/triax-eoc-exporter/types"
+)
+
+func (b *backend) SystemInfo(ctx context.Context) (*types.SystemInfo, error) {
+	capabilities := capabilitiesResponse{}
+	if err := b.Get(ctx, capabilitiesPath, &capabilities); err != nil {
+		return nil, err
+	}
+
+	response := systemResponse{}
+	if err := b.Get(ctx, systemPath, &response); err != nil {
+		return nil, err
+	}
+
+	return &types.SystemInfo{
+		Serial:   capabilities.Product.Serial,
+		Mac:      capabilities.Product.Mac,
+		Model:    capabilities.Product.Model,
+		Name:     response.System.Name,
+		Firmware: response.System.Version,
+		Uptime:   response.System.Uptime,
+	}, nil
+}
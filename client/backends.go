@@ -2,27 +2,135 @@ package client
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"slices"
 
 	"github.com/digineo/triax-eoc-exporter/types"
 )
 
 type builderFunction func(context.Context, *Client) (types.Backend, error)
 
-var backends []builderFunction
+type registeredBackend struct {
+	name     string
+	versions types.VersionRange
+	build    builderFunction
+}
+
+var backends []registeredBackend
+
+// Register adds a backend supporting the given range of firmware versions.
+func Register(name string, versions types.VersionRange, f builderFunction) {
+	backends = append(backends, registeredBackend{name, versions, f})
+}
+
+// BackendInfo describes the backend selected for a controller.
+type BackendInfo struct {
+	Name      string // name of the backend
+	Firmware  string // detected firmware version, empty if unknown
+	Supported bool   // whether the firmware is within the range of the backend
+}
+
+// Try logs in with the registered backends, newest first, until one
+// works. The firmware version detected by that backend then selects the
+// backend whose version range contains it. Otherwise the returned error
+// wraps types.ErrNoBackend and the errors of all backends.
+func Try(ctx context.Context, client *Client) (types.Backend, BackendInfo, error) {
+	candidates := slices.Clone(backends)
+	slices.SortStableFunc(candidates, func(a, b registeredBackend) int {
+		return -compareMin(a.versions, b.versions)
+	})
+
+	errs := []error{types.ErrNoBackend}
+	for i := range candidates {
+		backend, err := candidates[i].build(ctx, client)
+		if backend == nil {
+			slog.Info("backend not working", "backend", candidates[i].name, "error", err)
+			errs = append(errs, err)
+			continue
+		}
+
+		info := BackendInfo{Name: candidates[i].name, Firmware: probeFirmware(ctx, backend)}
+		info.Supported = candidates[i].versions.Contains(info.Firmware)
+
+		if chosen := selectBackend(candidates, info.Firmware); chosen != nil && chosen.name != info.Name {
+			if other, err := chosen.build(ctx, client); other != nil {
+				backend = other
+				info.Name = chosen.name
+				info.Supported = chosen.versions.Contains(info.Firmware)
+			} else {
+				slog.Warn("selected backend not working", "backend", chosen.name, "firmware", info.Firmware, "error", err)
+			}
+		}
+
+		if !info.Supported {
+			slog.Warn("firmware not supported by backend",
+				"host", client.endpoint.Host,
+				"backend", info.Name,
+				"firmware", info.Firmware,
+			)
+		}
+
+		backendSelectionsTotal.WithLabelValues(client.name, info.Name).Inc()
+		return backend, info, nil
+	}
 
-func Register(f builderFunction) {
-	backends = append(backends, f)
+	backendSelectionsTotal.WithLabelValues(client.name, "none").Inc()
+	return nil, BackendInfo{}, errors.Join(errs...)
 }
 
-func Try(ctx context.Context, client *Client) types.Backend {
-	for i := range backends {
-		backend, err := backends[i](ctx, client)
-		if backend != nil {
-			return backend
+// selectBackend returns the backend for the firmware version from the
+// candidates sorted newest first. For versions outside of all ranges, it
+// returns the nearest one: the newest backend whose range starts at or
+// below the version, or the oldest backend. Unknown versions select none.
+func selectBackend(candidates []registeredBackend, firmware string) *registeredBackend {
+	if firmware == "" || len(candidates) == 0 {
+		return nil
+	}
+
+	for i := range candidates {
+		if candidates[i].versions.Contains(firmware) {
+			return &candidates[i]
+		}
+	}
+
+	for i := range candidates {
+		if min := candidates[i].versions.Min; min == "" || types.CompareVersions(firmware, min) >= 0 {
+			return &candidates[i]
 		}
-		slog.Info("backend not working", "error", err)
 	}
 
-	return nil
+	return &candidates[len(candidates)-1]
+}
+
+// compareMin compares the lower bounds of two version ranges. An empty
+// bound is lower than any version.
+func compareMin(a, b types.VersionRange) int {
+	switch {
+	case a.Min == b.Min:
+		return 0
+	case a.Min == "":
+		return -1
+	case b.Min == "":
+		return 1
+	default:
+		return types.CompareVersions(a.Min, b.Min)
+	}
+}
+
+// probeFirmware returns the firmware version detected by the backend, or
+// an empty string if unknown.
+func probeFirmware(ctx context.Context, backend types.Backend) string {
+	prober, ok := backend.(types.FirmwareProber)
+	if !ok {
+		return ""
+	}
+
+	version, err := prober.Firmware(ctx)
+	if err != nil {
+		slog.Warn("detecting firmware failed", "error", err)
+		return ""
+	}
+
+	return version
 }
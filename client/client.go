@@ -3,80 +3,89 @@ package client
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
-	"net/http/cookiejar"
 	"net/url"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/digineo/triax-eoc-exporter/types"
-	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/sync/singleflight"
 )
 
+// Client is safe for concurrent use.
 type Client struct {
-	endpoint *url.URL
-	Username string
-	Password string
-	backend  types.Backend
-}
-
-var HTTPClient = http.Client{
-	Timeout: time.Second * 30,
-	CheckRedirect: func(req *http.Request, via []*http.Request) error {
-		return http.ErrUseLastResponse
-	},
-}
-
-func init() {
-	HTTPClient.Jar, _ = cookiejar.New(nil) // error is always nil
-	HTTPClient.Transport = &http.Transport{
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: true,
-		},
-	}
+	name         string // used in metric labels
+	endpoint     *url.URL
+	httpClient   *http.Client
+	retries      int
+	retryBackoff time.Duration
+	Username     string
+	Password     string
+
+	mu           sync.Mutex // protects backend, snapshot and errors
+	backend      types.Backend
+	backendInfo  BackendInfo
+	snapshot     *snapshot
+	scrapeErrors map[string]int
+	lastError    *ScrapeError
+	cacheTTL     time.Duration
+	fetchTimeout time.Duration // bounds a fetch shared by several scrapes
+	flight       singleflight.Group
+
+	// WirelessClients enables per-client metrics, if not nil
+	WirelessClients *types.ClientFilter
+
+	// ExpectedConfigHash is the reference for detecting config drift
+	// of endpoints. If empty, the most common hash is used.
+	ExpectedConfigHash string
 }
 
 // NewClient creates a new Client instance. The URL must embed login
-// credentials.
-func NewClient(endpoint *url.URL) (*Client, error) {
+// credentials. Every client uses its own HTTP transport and cookie jar.
+func NewClient(endpoint *url.URL, opts Options) (*Client, error) {
 	userinfo := endpoint.User
 	if userinfo == nil {
 		return nil, types.ErrMissingCredentials
 	}
 
+	if opts.Name == "" {
+		opts.Name = endpoint.Host
+	}
+
+	httpClient, err := newHTTPClient(endpoint.Host, &opts)
+	if err != nil {
+		return nil, err
+	}
+
 	pwd, _ := userinfo.Password()
 	client := &Client{
-		endpoint: endpoint,
-		Username: userinfo.Username(),
-		Password: pwd,
+		name:         opts.Name,
+		endpoint:     endpoint,
+		httpClient:   httpClient,
+		retries:      opts.Retries,
+		retryBackoff: orDefault(opts.RetryBackoff, defaultRetryBackoff),
+		cacheTTL:     opts.CacheTTL,
+		fetchTimeout: orDefault(opts.Timeout, defaultTimeout) * time.Duration(opts.Retries+1),
+		Username:     userinfo.Username(),
+		Password:     pwd,
 	}
 	return client, nil
 }
 
-func (c *Client) Get(ctx context.Context, path string, res interface{}) error {
-	return c.ApiRequest(ctx, http.MethodGet, path, nil, res)
-}
-
-const luaConfigPath = "/cgi.lua/config"
-
-// GetConfig fetches the configuration from the controller
-func (c *Client) GetConfig(ctx context.Context) (json.RawMessage, error) {
-	msg := json.RawMessage{}
-	err := c.Get(ctx, luaConfigPath, &msg)
-	return msg, err
+// Name returns the name of the controller used in metric labels.
+func (c *Client) Name() string {
+	return c.name
 }
 
-// SetConfig sets the configuration in the controller
-func (c *Client) SetConfig(ctx context.Context, raw json.RawMessage) error {
-	res := json.RawMessage{}
-	err := c.ApiRequest(ctx, http.MethodPost, luaConfigPath, raw, res)
-	return err
+func (c *Client) Get(ctx context.Context, path string, res interface{}) error {
+	return c.ApiRequest(ctx, http.MethodGet, path, nil, res)
 }
 
 func (c *Client) SetCookie(nameAndValue string) {
@@ -93,7 +102,7 @@ func (c *Client) SetCookie(nameAndValue string) {
 	)
 
 	// Set cookie from response
-	HTTPClient.Jar.SetCookies(c.endpoint, []*http.Cookie{{
+	c.httpClient.Jar.SetCookies(c.endpoint, []*http.Cookie{{
 		Name:   nameAndValue[:i],
 		Value:  nameAndValue[i+1:],
 		MaxAge: 0,
@@ -101,29 +110,91 @@ func (c *Client) SetCookie(nameAndValue string) {
 }
 
 func (c *Client) withBackend(ctx context.Context, f func(types.Backend) error) error {
-	if c.backend == nil {
-		err := c.setupBackend(ctx)
-		if err != nil {
+	c.mu.Lock()
+	backend := c.backend
+	c.mu.Unlock()
+
+	if backend == nil {
+		var err error
+		if backend, err = c.setupBackend(ctx, nil); err != nil {
 			return err
 		}
 	}
 
-	return f(c.backend)
+	return f(backend)
 }
 
-func (c *Client) setupBackend(ctx context.Context) error {
-	c.backend = Try(ctx, c)
-	if c.backend == nil {
-		return errors.New("no usable backend found")
+// setupBackend performs a login, unless a concurrent caller has already
+// replaced the stale backend. Concurrent logins are deduplicated.
+func (c *Client) setupBackend(ctx context.Context, stale types.Backend) (types.Backend, error) {
+	result, err, _ := c.flight.Do("login", func() (any, error) {
+		c.mu.Lock()
+		current := c.backend
+		c.mu.Unlock()
+
+		if current != nil && current != stale {
+			return current, nil
+		}
+
+		backend, info, err := Try(ctx, c)
+		if err != nil {
+			return nil, err
+		}
+
+		c.mu.Lock()
+		c.backend = backend
+		c.backendInfo = info
+		c.mu.Unlock()
+
+		return backend, nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	return result.(types.Backend), nil
+}
+
+// BackendInfo returns the selected backend, if any.
+func (c *Client) BackendInfo() (BackendInfo, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.backendInfo, c.backend != nil
+}
+
+// GetConfig fetches the configuration from the controller
+func (c *Client) GetConfig(ctx context.Context) (config json.RawMessage, err error) {
+	err = c.withBackend(ctx, func(backend types.Backend) error {
+		config, err = backend.GetConfig(ctx)
+		return err
+	})
+	return
 }
 
-func (c *Client) Collect(ctx context.Context, ch chan<- prometheus.Metric) error {
+// SetConfig sets the configuration in the controller
+func (c *Client) SetConfig(ctx context.Context, config json.RawMessage) error {
 	return c.withBackend(ctx, func(backend types.Backend) error {
-		return backend.Collect(ctx, ch)
+		return backend.SetConfig(ctx, config)
+	})
+}
+
+// Endpoints fetches the endpoint inventory from the controller
+func (c *Client) Endpoints(ctx context.Context) (endpoints []types.Endpoint, err error) {
+	err = c.withBackend(ctx, func(backend types.Backend) error {
+		endpoints, err = backend.Endpoints(ctx)
+		return err
+	})
+	return
+}
+
+// SystemInfo fetches infos about the controller
+func (c *Client) SystemInfo(ctx context.Context) (info *types.SystemInfo, err error) {
+	err = c.withBackend(ctx, func(backend types.Backend) error {
+		info, err = backend.SystemInfo(ctx)
+		return err
 	})
+	return
 }
 
 // calls apiRequestRaw and does a login on unauthorized status
@@ -137,7 +208,7 @@ func (c *Client) ApiRequest(ctx context.Context, method, path string, request, r
 
 		if errors.As(err, &errStatus) && errStatus.Status == http.StatusUnauthorized && !retried {
 
-			if c.setupBackend(ctx) == nil {
+			if _, err := c.setupBackend(ctx, backend); err == nil {
 				retried = true
 				goto retry
 			}
@@ -156,32 +227,52 @@ func (c *Client) ApiRequest(ctx context.Context, method, path string, request, r
 func (c *Client) ApiRequestRaw(ctx context.Context, method, path string, request, response interface{}) (*http.Response, error) {
 	url := fmt.Sprintf("%s://%s/%s", c.endpoint.Scheme, c.endpoint.Host, strings.TrimPrefix(path, "/"))
 
-	slog.Info("HTTP Request", "method", method, "url", url)
-
-	var body io.Reader
+	var body []byte
 	if request != nil {
 		var buf bytes.Buffer
 		if err := json.NewEncoder(&buf).Encode(request); err != nil {
 			return nil, fmt.Errorf("encoding body failed: %w", err)
 		}
-		body = &buf
+		body = buf.Bytes()
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	for retry := 0; ; retry++ {
+		res, err := c.doRequest(ctx, method, url, path, body, response)
+		if retry >= c.retries || !retryable(ctx, method, err) {
+			return res, err
+		}
+
+		delay := backoff(c.retryBackoff, retry)
+		retriesTotal.WithLabelValues(c.name, metricPath(path)).Inc()
+		slog.Warn("HTTP Request failed, retrying", "method", method, "url", url, "delay", delay, "error", err)
+
+		if err := sleep(ctx, delay); err != nil {
+			return res, err
+		}
+	}
+}
+
+// doRequest performs a single attempt of ApiRequestRaw.
+func (c *Client) doRequest(ctx context.Context, method, url, path string, body []byte, response interface{}) (*http.Response, error) {
+	slog.Info("HTTP Request", "method", method, "url", url)
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("cannot construct request: %w", err)
 	}
 
 	req.Header.Set("Accept", "application/json")
-	if request != nil {
+	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
 
-	res, err := HTTPClient.Do(req)
+	res, err := c.httpClient.Do(req)
 	if err != nil {
+		requestsTotal.WithLabelValues(c.name, metricPath(path), "error").Inc()
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 	defer res.Body.Close()
+	requestsTotal.WithLabelValues(c.name, metricPath(path), strconv.Itoa(res.StatusCode)).Inc()
 
 	if res.StatusCode != http.StatusOK {
 		data, _ := io.ReadAll(res.Body)
@@ -213,6 +304,7 @@ func (c *Client) ApiRequestRaw(ctx context.Context, method, path string, request
 		err = json.Unmarshal(jsonData, &response)
 
 		if err != nil {
+			decodeErrorsTotal.WithLabelValues(c.name, metricPath(path)).Inc()
 			slog.Error("response received", "json", string(jsonData))
 			return res, fmt.Errorf("decoding response failed: %w", err)
 		}
@@ -222,3 +314,11 @@ func (c *Client) ApiRequestRaw(ctx context.Context, method, path string, request
 
 	return res, nil
 }
+
+// metricPath strips the query and leading slash from a request path.
+func metricPath(path string) string {
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	return strings.TrimPrefix(path, "/")
+}
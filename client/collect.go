@@ -0,0 +1,89 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/digineo/triax-eoc-exporter/types"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// snapshot holds the result of a single upstream fetch.
+type snapshot struct {
+	metrics []prometheus.Metric
+	fetched time.Time
+}
+
+// Collect sends the metrics of the controller to the channel and returns
+// the time they were fetched. Concurrent calls share a single upstream
+// fetch, and the result is reused for the configured cache TTL.
+func (c *Client) Collect(ctx context.Context, ch chan<- prometheus.Metric) (time.Time, error) {
+	snap, err := c.getSnapshot(ctx)
+	if err != nil {
+		c.recordError(err)
+		return time.Time{}, err
+	}
+
+	for _, m := range snap.metrics {
+		ch <- m
+	}
+
+	return snap.fetched, nil
+}
+
+func (c *Client) getSnapshot(ctx context.Context) (*snapshot, error) {
+	c.mu.Lock()
+	snap := c.snapshot
+	c.mu.Unlock()
+
+	if snap != nil && time.Since(snap.fetched) < c.cacheTTL {
+		return snap, nil
+	}
+
+	result, err, _ := c.flight.Do("collect", func() (any, error) {
+		// the fetch must not be aborted when the first of several waiting
+		// scrapes goes away, but must not outlive all of them either
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.fetchTimeout)
+		defer cancel()
+
+		snap, err := c.fetch(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		c.mu.Lock()
+		c.snapshot = snap
+		c.mu.Unlock()
+
+		return snap, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.(*snapshot), nil
+}
+
+// fetch collects the metrics from the backend.
+func (c *Client) fetch(ctx context.Context) (*snapshot, error) {
+	snap := &snapshot{fetched: time.Now()}
+
+	ch := make(chan prometheus.Metric)
+	done := make(chan error, 1)
+	go func() {
+		done <- c.withBackend(ctx, func(backend types.Backend) error {
+			return backend.Collect(ctx, ch)
+		})
+		close(ch)
+	}()
+
+	for m := range ch {
+		snap.metrics = append(snap.metrics, m)
+	}
+
+	if err := <-done; err != nil {
+		return nil, err
+	}
+
+	return snap, nil
+}
@@ -0,0 +1,47 @@
+package client
+
+import (
+	"time"
+
+	"github.com/digineo/triax-eoc-exporter/types"
+)
+
+// ScrapeError describes the last failed scrape. The message is a short
+// summary, the full error is only logged.
+type ScrapeError struct {
+	Reason  string
+	Message string
+	Time    time.Time
+}
+
+// recordError counts a failed scrape by its reason.
+func (c *Client) recordError(err error) {
+	reason := types.Classify(err)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.scrapeErrors == nil {
+		c.scrapeErrors = make(map[string]int)
+	}
+	c.scrapeErrors[reason]++
+	c.lastError = &ScrapeError{
+		Reason:  reason,
+		Message: types.Summary(err),
+		Time:    time.Now(),
+	}
+}
+
+// ScrapeErrors returns the number of failed scrapes by reason and the
+// last error, if any.
+func (c *Client) ScrapeErrors() (map[string]int, *ScrapeError) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	counts := make(map[string]int, len(types.Reasons))
+	for _, reason := range types.Reasons {
+		counts[reason] = c.scrapeErrors[reason]
+	}
+
+	return counts, c.lastError
+}
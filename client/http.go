@@ -0,0 +1,167 @@
+package client
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"time"
+)
+
+// Options configures the HTTP connection to a controller.
+type Options struct {
+	// name of the controller in metric labels, the host if empty
+	Name string
+
+	// maximum number of idle connections, zero means the default of 2
+	MaxIdleConns int
+
+	// maximum number of connections, zero means unlimited
+	MaxConnsPerHost int
+
+	// how long idle connections are kept open, zero means 90 seconds
+	IdleConnTimeout time.Duration
+
+	// PEM encoded CA certificates, the system roots are used if empty
+	TLSCAFile string
+
+	// server name for the certificate verification, the host if empty
+	TLSServerName string
+
+	// pinned SHA-256 fingerprint of the server certificate
+	TLSFingerprint string
+
+	// disables the certificate verification
+	TLSInsecure bool
+
+	// store for trust on first use, if not nil
+	TLSStore *FingerprintStore
+
+	// timeout for establishing a TCP connection, zero means 30 seconds
+	ConnectTimeout time.Duration
+
+	// timeout for the TLS handshake, zero means 10 seconds
+	TLSHandshakeTimeout time.Duration
+
+	// overall timeout of a single request, zero means 30 seconds
+	Timeout time.Duration
+
+	// number of retries for failed GET requests
+	Retries int
+
+	// initial delay between retries, zero means 500 milliseconds
+	RetryBackoff time.Duration
+
+	// how long collected metrics are reused, zero disables the cache
+	CacheTTL time.Duration
+
+	// proxy for all requests (http, https or socks5), credentials may
+	// be embedded in the URL
+	ProxyURL *url.URL
+
+	// directory for recording all requests and responses, if not empty
+	RecordDir string
+
+	// removes passwords, cookies and MAC addresses from recordings
+	RecordRedact bool
+
+	// directory with recordings to answer requests from, no requests are
+	// sent to the controller if not empty
+	ReplayDir string
+}
+
+const (
+	defaultTimeout             = 30 * time.Second
+	defaultConnectTimeout      = 30 * time.Second
+	defaultTLSHandshakeTimeout = 10 * time.Second
+	defaultIdleConnTimeout     = 90 * time.Second
+	defaultRetryBackoff        = 500 * time.Millisecond
+)
+
+// orDefault returns the value or the default, if the value is zero.
+func orDefault(value, def time.Duration) time.Duration {
+	if value == 0 {
+		return def
+	}
+	return value
+}
+
+// newHTTPClient builds a HTTP client with its own transport and cookie jar.
+func newHTTPClient(host string, opts *Options) (*http.Client, error) {
+	tlsConfig, err := newTLSConfig(host, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	jar, _ := cookiejar.New(nil) // error is always nil
+
+	var transport http.RoundTripper = &http.Transport{
+		Proxy: proxyFunc(opts.ProxyURL),
+		DialContext: (&net.Dialer{
+			Timeout:   orDefault(opts.ConnectTimeout, defaultConnectTimeout),
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSHandshakeTimeout: orDefault(opts.TLSHandshakeTimeout, defaultTLSHandshakeTimeout),
+		MaxIdleConnsPerHost: opts.MaxIdleConns,
+		MaxConnsPerHost:     opts.MaxConnsPerHost,
+		IdleConnTimeout:     orDefault(opts.IdleConnTimeout, defaultIdleConnTimeout),
+		TLSClientConfig:     tlsConfig,
+	}
+
+	if opts.ReplayDir != "" {
+		transport = &replayer{dir: opts.ReplayDir}
+	}
+
+	if opts.RecordDir != "" {
+		if err := os.MkdirAll(opts.RecordDir, 0o700); err != nil {
+			return nil, err
+		}
+		transport = &recorder{
+			next:   transport,
+			dir:    opts.RecordDir,
+			redact: opts.RecordRedact,
+		}
+	}
+
+	client := &http.Client{
+		Timeout: orDefault(opts.Timeout, defaultTimeout),
+		Jar:     jar,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+		Transport: transport,
+	}
+
+	return client, nil
+}
+
+// proxyFunc returns the proxy function for the transport. Without a proxy
+// URL, no proxy is used.
+func proxyFunc(proxyURL *url.URL) func(*http.Request) (*url.URL, error) {
+	if proxyURL == nil {
+		return nil
+	}
+	return http.ProxyURL(proxyURL)
+}
+
+// ParseProxyURL parses and validates a proxy URL.
+func ParseProxyURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q", u.Scheme)
+	}
+
+	if u.Host == "" {
+		return nil, fmt.Errorf("missing proxy host in %q", raw)
+	}
+
+	return u, nil
+}
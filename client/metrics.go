@@ -0,0 +1,71 @@
+package client
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// metrics about the exporter itself
+var (
+	requestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "triax",
+		Subsystem: "eoc_exporter",
+		Name:      "requests_total",
+		Help:      "number of HTTP requests sent to controllers",
+	}, []string{"controller", "path", "code"})
+
+	retriesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "triax",
+		Subsystem: "eoc_exporter",
+		Name:      "request_retries_total",
+		Help:      "number of retried HTTP requests to controllers",
+	}, []string{"controller", "path"})
+
+	decodeErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "triax",
+		Subsystem: "eoc_exporter",
+		Name:      "decode_errors_total",
+		Help:      "number of controller responses that could not be decoded",
+	}, []string{"controller", "path"})
+
+	loginsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "triax",
+		Subsystem: "eoc_exporter",
+		Name:      "logins_total",
+		Help:      "number of login attempts by backend and result",
+	}, []string{"controller", "backend", "result"})
+
+	backendSelectionsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "triax",
+		Subsystem: "eoc_exporter",
+		Name:      "backend_selections_total",
+		Help:      "number of backend selections by chosen backend",
+	}, []string{"controller", "backend"})
+
+	fingerprintMismatchesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "triax",
+		Subsystem: "eoc_exporter",
+		Name:      "tls_fingerprint_mismatches_total",
+		Help:      "number of TLS connections refused due to an unexpected certificate fingerprint",
+	}, []string{"controller"})
+)
+
+// MustRegisterMetrics registers the client metrics.
+func MustRegisterMetrics(reg prometheus.Registerer) {
+	reg.MustRegister(
+		requestsTotal,
+		retriesTotal,
+		decodeErrorsTotal,
+		loginsTotal,
+		backendSelectionsTotal,
+		fingerprintMismatchesTotal,
+	)
+}
+
+// CountLogin records a login attempt of a backend.
+func (c *Client) CountLogin(backend string, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	loginsTotal.WithLabelValues(c.name, backend, result).Inc()
+}
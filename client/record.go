@@ -0,0 +1,161 @@
+package client
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// fixture is a recorded request and response pair.
+type fixture struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	RequestBody string      `json:"request_body,omitempty"`
+	Status      int         `json:"status"`
+	Header      http.Header `json:"header"`
+	Body        string      `json:"body"`
+}
+
+var (
+	fixtureNameRegex = regexp.MustCompile(`[^A-Za-z0-9.-]+`)
+	macRegex         = regexp.MustCompile(`(?i)\b[0-9a-f]{2}([:-][0-9a-f]{2}){5}\b`)
+	passwordRegex    = regexp.MustCompile(`("password"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+)
+
+const redacted = "REDACTED"
+
+// fixtureFile returns the file name of the fixture for a request.
+func fixtureFile(dir string, req *http.Request) string {
+	name := strings.TrimPrefix(req.URL.Path, "/")
+	if req.URL.RawQuery != "" {
+		name += "?" + req.URL.RawQuery
+	}
+	name = strings.Trim(fixtureNameRegex.ReplaceAllString(name, "_"), "_")
+
+	return filepath.Join(dir, req.Method+"_"+name+".json")
+}
+
+// recorder is a transport writing every request and response pair to a
+// fixture directory.
+type recorder struct {
+	next   http.RoundTripper
+	dir    string
+	redact bool
+}
+
+func (r *recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	f := fixture{
+		Method: req.Method,
+		URL:    req.URL.String(),
+	}
+
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		f.RequestBody = string(body)
+	}
+
+	res, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+
+	f.Status = res.StatusCode
+	f.Header = res.Header.Clone()
+	f.Body = string(body)
+
+	if r.redact {
+		f.redact()
+	}
+
+	if err := f.write(fixtureFile(r.dir, req)); err != nil {
+		return nil, fmt.Errorf("recording failed: %w", err)
+	}
+
+	return res, nil
+}
+
+// redact removes passwords and cookie values, and replaces MAC addresses
+// by stable pseudonyms.
+func (f *fixture) redact() {
+	for _, s := range []*string{&f.RequestBody, &f.Body} {
+		*s = passwordRegex.ReplaceAllString(*s, `$1"`+redacted+`"`)
+		*s = macRegex.ReplaceAllStringFunc(*s, pseudoMac)
+	}
+
+	cookies := f.Header["Set-Cookie"]
+	for i, cookie := range cookies {
+		name, rest, _ := strings.Cut(cookie, "=")
+		cookies[i] = name + "=" + redacted
+		if _, attrs, found := strings.Cut(rest, ";"); found {
+			cookies[i] += ";" + attrs
+		}
+	}
+}
+
+// pseudoMac maps a MAC address to a locally administered address derived
+// from its hash, so relations between redacted fixtures are kept.
+func pseudoMac(mac string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(mac)))
+	return fmt.Sprintf("02:%02x:%02x:%02x:%02x:%02x", sum[0], sum[1], sum[2], sum[3], sum[4])
+}
+
+func (f *fixture) write(file string) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, data, 0o600)
+}
+
+// replayer is a transport answering requests from a fixture directory.
+type replayer struct {
+	dir string
+}
+
+func (r *replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	data, err := os.ReadFile(fixtureFile(r.dir, req))
+	if err != nil {
+		return nil, fmt.Errorf("replay failed: %w", err)
+	}
+
+	f := fixture{}
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("replay failed: %w", err)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", f.Status, http.StatusText(f.Status)),
+		StatusCode:    f.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        f.Header,
+		Body:          io.NopCloser(strings.NewReader(f.Body)),
+		ContentLength: int64(len(f.Body)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,64 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordReplay(t *testing.T) {
+	dir := t.TempDir()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "sid=secret; Path=/")
+		w.Write([]byte(`{"mac":"00:11:22:AA:BB:CC","password":"wifi-key"}`))
+	}))
+	defer srv.Close()
+
+	endpoint, _ := url.Parse(srv.URL)
+	endpoint.User = url.UserPassword("admin", "secret")
+
+	type response struct {
+		Mac      string `json:"mac"`
+		Password string `json:"password"`
+	}
+	login := map[string]string{"username": "admin", "password": "secret"}
+
+	// record
+	c, err := NewClient(endpoint, Options{RecordDir: dir, RecordRedact: true})
+	require.NoError(t, err)
+
+	recorded := response{}
+	_, err = c.ApiRequestRaw(context.Background(), http.MethodPost, "cgi.lua/login?x=1", &login, &recorded)
+	require.NoError(t, err)
+	assert.Equal(t, "00:11:22:AA:BB:CC", recorded.Mac)
+
+	data, err := os.ReadFile(filepath.Join(dir, "POST_cgi.lua_login_x_1.json"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "secret")
+	assert.NotContains(t, string(data), "wifi-key")
+	assert.NotContains(t, string(data), "00:11:22:AA:BB:CC")
+
+	// replay without the server
+	srv.Close()
+	c, err = NewClient(endpoint, Options{ReplayDir: dir})
+	require.NoError(t, err)
+
+	replayed := response{}
+	res, err := c.ApiRequestRaw(context.Background(), http.MethodPost, "cgi.lua/login?x=1", &login, &replayed)
+	require.NoError(t, err)
+	assert.Equal(t, pseudoMac("00:11:22:aa:bb:cc"), replayed.Mac)
+	assert.Equal(t, redacted, replayed.Password)
+	assert.Equal(t, "sid=REDACTED; Path=/", res.Header.Get("Set-Cookie"))
+
+	// unknown requests fail
+	_, err = c.ApiRequestRaw(context.Background(), http.MethodGet, "cgi.lua/unknown", nil, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,73 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/digineo/triax-eoc-exporter/types"
+)
+
+// retryable reports whether a failed request may be repeated. Only GET
+// requests failing due to timeouts, connection resets or server errors are
+// retried. TLS verification failures, unknown hosts and refused connections
+// are permanent.
+func retryable(ctx context.Context, method string, err error) bool {
+	if err == nil || method != http.MethodGet || ctx.Err() != nil {
+		return false
+	}
+
+	if errStatus := (&types.ErrUnexpectedStatus{}); errors.As(err, &errStatus) {
+		return errStatus.Status >= http.StatusInternalServerError
+	}
+
+	if dnsErr := (&net.DNSError{}); errors.As(err, &dnsErr) {
+		return dnsErr.IsTimeout
+	}
+
+	if types.Classify(err) == types.ReasonTLS {
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// maxRetryBackoff limits the delay between retries before jitter.
+const maxRetryBackoff = 30 * time.Second
+
+// backoff returns the jittered delay before the given retry, starting at 0.
+func backoff(base time.Duration, retry int) time.Duration {
+	delay := maxRetryBackoff
+	if base < maxRetryBackoff>>retry {
+		delay = base << retry
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	return delay/2 + rand.N(delay)
+}
+
+// sleep waits for the given duration or until the context is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
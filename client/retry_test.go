@@ -0,0 +1,117 @@
+package client
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/digineo/triax-eoc-exporter/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetry(t *testing.T) {
+	var requests atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= 2 {
+			http.Error(w, "busy", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	endpoint, _ := url.Parse(srv.URL)
+	endpoint.User = url.UserPassword("admin", "secret")
+
+	c, err := NewClient(endpoint, Options{
+		Retries:      2,
+		RetryBackoff: time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	res := struct{ OK bool }{}
+	_, err = c.ApiRequestRaw(context.Background(), http.MethodGet, "/", nil, &res)
+	require.NoError(t, err)
+	assert.True(t, res.OK)
+	assert.EqualValues(t, 3, requests.Load())
+
+	// POST requests are not retried
+	requests.Store(0)
+	_, err = c.ApiRequestRaw(context.Background(), http.MethodPost, "/", &res, nil)
+	errStatus := &types.ErrUnexpectedStatus{}
+	require.ErrorAs(t, err, &errStatus)
+	assert.Equal(t, http.StatusServiceUnavailable, errStatus.Status)
+	assert.EqualValues(t, 1, requests.Load())
+}
+
+func TestRetryable(t *testing.T) {
+	ctx := context.Background()
+	requestFailed := func(err error) error {
+		return fmt.Errorf("request failed: %w", &url.Error{Op: "Get", URL: "https://192.0.2.1/", Err: err})
+	}
+	dial := func(err error) error {
+		return requestFailed(&net.OpError{Op: "dial", Net: "tcp", Err: err})
+	}
+	read := func(err error) error {
+		return requestFailed(&net.OpError{Op: "read", Net: "tcp", Err: err})
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"server error", &types.ErrUnexpectedStatus{Status: http.StatusServiceUnavailable}, true},
+		{"client error", &types.ErrUnexpectedStatus{Status: http.StatusNotFound}, false},
+		{"timeout", requestFailed(os.ErrDeadlineExceeded), true},
+		{"connection reset", read(syscall.ECONNRESET), true},
+		{"unexpected EOF", requestFailed(io.EOF), true},
+		{"connection refused", dial(syscall.ECONNREFUSED), false},
+		{"unknown host", requestFailed(&net.DNSError{Err: "no such host", Name: "ctrl", IsNotFound: true}), false},
+		{"DNS timeout", requestFailed(&net.DNSError{Err: "i/o timeout", Name: "ctrl", IsTimeout: true}), true},
+		{"unknown authority", requestFailed(x509.UnknownAuthorityError{}), false},
+		{"fingerprint mismatch", requestFailed(&types.ErrFingerprintMismatch{}), false},
+		{"decode error", errors.New("decoding response failed"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, retryable(ctx, http.MethodGet, tt.err), "%v", tt.err)
+		})
+	}
+
+	assert.False(t, retryable(ctx, http.MethodPost, read(syscall.ECONNRESET)))
+}
+
+func TestBackoff(t *testing.T) {
+	for retry := range 4 {
+		base := 100 * time.Millisecond << retry
+		delay := backoff(100*time.Millisecond, retry)
+		assert.GreaterOrEqual(t, delay, base/2)
+		assert.Less(t, delay, base*3/2)
+	}
+}
+
+func TestBackoffLimits(t *testing.T) {
+	assert.Zero(t, backoff(0, 1))
+	assert.Zero(t, backoff(-time.Second, 1))
+
+	for _, retry := range []int{10, 63, 100} {
+		delay := backoff(time.Second, retry)
+		assert.GreaterOrEqual(t, delay, maxRetryBackoff/2, "retry %d", retry)
+		assert.Less(t, delay, maxRetryBackoff*3/2, "retry %d", retry)
+	}
+}
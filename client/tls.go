@@ -0,0 +1,160 @@
+package client
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/digineo/triax-eoc-exporter/types"
+)
+
+// newTLSConfig builds the TLS configuration for a controller. Without a
+// pinned fingerprint, the certificate chain is verified against the CA
+// bundle or the system roots.
+func newTLSConfig(host string, opts *Options) (*tls.Config, error) {
+	cfg := &tls.Config{
+		ServerName:         opts.TLSServerName,
+		InsecureSkipVerify: opts.TLSInsecure,
+	}
+
+	if opts.TLSCAFile != "" {
+		pool, err := LoadCAFile(opts.TLSCAFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.RootCAs = pool
+	}
+
+	pinned := NormalizeFingerprint(opts.TLSFingerprint)
+	if pinned == "" && opts.TLSStore == nil {
+		return cfg, nil
+	}
+
+	// the fingerprint replaces the chain verification
+	cfg.InsecureSkipVerify = true
+	cfg.VerifyConnection = func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return errors.New("no peer certificate")
+		}
+
+		actual := Fingerprint(cs.PeerCertificates[0])
+		expected := pinned
+		if expected == "" {
+			var err error
+			if expected, err = opts.TLSStore.Lookup(host, actual); err != nil {
+				return err
+			}
+		}
+
+		if actual != expected {
+			fingerprintMismatchesTotal.WithLabelValues(opts.Name).Inc()
+			return &types.ErrFingerprintMismatch{
+				Host:     host,
+				Expected: expected,
+				Actual:   actual,
+			}
+		}
+
+		return nil
+	}
+
+	return cfg, nil
+}
+
+// LoadCAFile reads a PEM encoded CA bundle.
+func LoadCAFile(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading CA file failed: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in %s", path)
+	}
+
+	return pool, nil
+}
+
+// Fingerprint returns the hex encoded SHA-256 fingerprint of a certificate.
+func Fingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// NormalizeFingerprint removes colons and whitespace and converts the
+// fingerprint to lower case.
+func NormalizeFingerprint(fingerprint string) string {
+	return strings.ToLower(strings.NewReplacer(":", "", " ", "").Replace(fingerprint))
+}
+
+// FingerprintStore persists the first seen certificate fingerprint of each
+// controller (trust on first use).
+type FingerprintStore struct {
+	path         string
+	mu           sync.Mutex
+	fingerprints map[string]string
+}
+
+// NewFingerprintStore loads the fingerprints from the given file. A missing
+// file is not an error.
+func NewFingerprintStore(path string) (*FingerprintStore, error) {
+	store := &FingerprintStore{
+		path:         path,
+		fingerprints: make(map[string]string),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &store.fingerprints); err != nil {
+		return nil, fmt.Errorf("decoding %s failed: %w", path, err)
+	}
+
+	return store, nil
+}
+
+// Lookup returns the stored fingerprint for the host. If none is stored yet,
+// the seen fingerprint is stored and returned.
+func (s *FingerprintStore) Lookup(host, seen string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if fingerprint, ok := s.fingerprints[host]; ok {
+		return fingerprint, nil
+	}
+
+	s.fingerprints[host] = seen
+	if err := s.save(); err != nil {
+		delete(s.fingerprints, host)
+		return "", err
+	}
+
+	return seen, nil
+}
+
+func (s *FingerprintStore) save() error {
+	data, err := json.MarshalIndent(s.fingerprints, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("writing %s failed: %w", tmp, err)
+	}
+
+	return os.Rename(tmp, s.path)
+}
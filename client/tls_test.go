@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"github.com/digineo/triax-eoc-exporter/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTLSTestServer(t *testing.T) (*httptest.Server, *url.URL) {
+	t.Helper()
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("{}"))
+	}))
+	t.Cleanup(srv.Close)
+
+	endpoint, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	endpoint.User = url.UserPassword("admin", "secret")
+
+	return srv, endpoint
+}
+
+func request(t *testing.T, endpoint *url.URL, opts Options) error {
+	t.Helper()
+
+	c, err := NewClient(endpoint, opts)
+	require.NoError(t, err)
+
+	_, err = c.ApiRequestRaw(context.Background(), http.MethodGet, "/", nil, nil)
+	return err
+}
+
+func TestTLSVerification(t *testing.T) {
+	srv, endpoint := newTLSTestServer(t)
+	fingerprint := Fingerprint(srv.Certificate())
+
+	// self-signed certificate is rejected by default
+	assert.Error(t, request(t, endpoint, Options{}))
+
+	// pinned fingerprint
+	assert.NoError(t, request(t, endpoint, Options{TLSFingerprint: fingerprint}))
+
+	err := request(t, endpoint, Options{TLSFingerprint: "00:11"})
+	mismatch := &types.ErrFingerprintMismatch{}
+	require.ErrorAs(t, err, &mismatch)
+	assert.Equal(t, fingerprint, mismatch.Actual)
+}
+
+func TestTrustOnFirstUse(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fingerprints.json")
+
+	store, err := NewFingerprintStore(path)
+	require.NoError(t, err)
+
+	srv, endpoint := newTLSTestServer(t)
+	assert.NoError(t, request(t, endpoint, Options{TLSStore: store}))
+
+	// the fingerprint survives a restart
+	store, err = NewFingerprintStore(path)
+	require.NoError(t, err)
+	seen, err := store.Lookup(endpoint.Host, "")
+	require.NoError(t, err)
+	assert.Equal(t, Fingerprint(srv.Certificate()), seen)
+
+	// another certificate for the same host is refused
+	store.fingerprints[endpoint.Host] = "changed"
+	err = request(t, endpoint, Options{TLSStore: store})
+	mismatch := &types.ErrFingerprintMismatch{}
+	assert.ErrorAs(t, err, &mismatch)
+}
@@ -3,6 +3,8 @@ package exporter
 import (
 	"context"
 	"log/slog"
+	"strconv"
+	"time"
 
 	"github.com/digineo/triax-eoc-exporter/client"
 	"github.com/digineo/triax-eoc-exporter/types"
@@ -14,40 +16,46 @@ type triaxCollector struct {
 	ctx    context.Context
 }
 
+var scrapeDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: "triax",
+	Subsystem: "eoc_exporter",
+	Name:      "scrape_duration_seconds",
+	Help:      "duration of controller scrapes",
+	Buckets:   []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+}, []string{"controller"})
+
 var _ prometheus.Collector = (*triaxCollector)(nil)
 
 func (t *triaxCollector) Describe(ch chan<- *prometheus.Desc) {
-	ch <- types.CtrlUp
-	ch <- types.CtrlUptime
-	ch <- types.CtrlInfo
-	ch <- types.CtrlLoad
-	ch <- types.CtrlMemoryTotal
-	ch <- types.CtrlMemoryFree
-	ch <- types.CtrlMemoryBuffered
-	ch <- types.CtrlMemoryShared
-	ch <- types.CtrlGhnNumOnline
-	ch <- types.CtrlGhnNumRegistered
-
-	ch <- types.NodeInfo
-	ch <- types.NodeStatus
-	ch <- types.NodeUptime
-	ch <- types.NodeLoad
-	ch <- types.NodeGhnPort
-	ch <- types.NodeClients
-
-	ch <- types.GhnSnrMin
-	ch <- types.GhnSnrAvg
-	ch <- types.GhnSnrMax
+	types.Describe(ch)
 }
 
 func (t *triaxCollector) Collect(ch chan<- prometheus.Metric) {
-	err := t.client.Collect(t.ctx, ch)
+	start := time.Now()
+	fetched, err := t.client.Collect(t.ctx, ch)
+	scrapeDuration.WithLabelValues(t.client.Name()).Observe(time.Since(start).Seconds())
 
 	// Write up
 	ch <- prometheus.MustNewConstMetric(types.CtrlUp, prometheus.GaugeValue, boolToFloat(err == nil))
 
+	if err == nil {
+		ch <- prometheus.MustNewConstMetric(types.CtrlDataAge, prometheus.GaugeValue, time.Since(fetched).Seconds())
+	}
+
+	if info, ok := t.client.BackendInfo(); ok {
+		ch <- prometheus.MustNewConstMetric(types.CtrlBackendInfo, prometheus.GaugeValue, 1, info.Name, info.Firmware, strconv.FormatBool(info.Supported))
+	}
+
+	counts, last := t.client.ScrapeErrors()
+	for reason, n := range counts {
+		ch <- prometheus.MustNewConstMetric(types.CtrlScrapeErrors, prometheus.CounterValue, float64(n), reason)
+	}
+	if last != nil {
+		ch <- prometheus.MustNewConstMetric(types.CtrlLastError, prometheus.GaugeValue, float64(last.Time.Unix()), last.Reason, last.Message)
+	}
+
 	if err != nil {
-		slog.Error("fetching failed", "error", err)
+		slog.Error("fetching failed", "reason", types.Classify(err), "error", err)
 	}
 }
 
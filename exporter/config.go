@@ -1,20 +1,36 @@
 package exporter
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
 	"net"
 	"net/url"
+	"regexp"
 	"strconv"
+	"sync"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	"github.com/digineo/triax-eoc-exporter/client"
+	"github.com/digineo/triax-eoc-exporter/types"
 )
 
 const defaultPort = 443
 
+// maxRetries limits the retries of failed requests
+const maxRetries = 10
+
 type Config struct {
+	// file for storing certificate fingerprints (trust on first use)
+	TLSStateFile string `toml:"tls_state_file"`
+
 	// list of Triax EoC controllers
 	Controllers []Controller `toml:"eoc-controller"`
+
+	fingerprints *client.FingerprintStore
+
+	mu sync.Mutex // protects the clients of the controllers
 }
 
 type Controller struct {
@@ -23,6 +39,53 @@ type Controller struct {
 	Port     uint16
 	Password string
 	client   *client.Client
+	filter   *types.ClientFilter
+	proxyURL *url.URL
+
+	// HTTP connection pool settings
+	MaxIdleConns    int           `toml:"max_idle_conns"`
+	MaxConnsPerHost int           `toml:"max_conns_per_host"`
+	IdleConnTimeout time.Duration `toml:"idle_conn_timeout"`
+
+	// timeouts and retries
+	ConnectTimeout      time.Duration `toml:"connect_timeout"`
+	TLSHandshakeTimeout time.Duration `toml:"tls_handshake_timeout"`
+	Timeout             time.Duration `toml:"timeout"`
+	Retries             int           `toml:"retries"`
+	RetryBackoff        time.Duration `toml:"retry_backoff"`
+
+	// proxy for reaching the controller
+	ProxyURL string `toml:"proxy_url"`
+
+	// recording and replaying of controller traffic
+	RecordDir    string `toml:"record_dir"`
+	RecordRedact bool   `toml:"record_redact"`
+	ReplayDir    string `toml:"replay_dir"`
+
+	// how long scraped data is reused
+	CacheTTL time.Duration `toml:"cache_ttl"`
+
+	// TLS settings
+	TLSCAFile            string `toml:"tls_ca_file"`
+	TLSServerName        string `toml:"tls_server_name"`
+	TLSFingerprintSHA256 string `toml:"tls_fingerprint_sha256"`
+	TLSTrustOnFirstUse   bool   `toml:"tls_trust_on_first_use"`
+	TLSInsecure          bool   `toml:"tls_insecure"`
+
+	// expected config hash of all endpoints
+	ExpectedConfigHash string `toml:"expected_config_hash"`
+
+	// opt-in for per-client wireless metrics
+	WirelessClients *WirelessClients `toml:"wireless_clients"`
+}
+
+type WirelessClients struct {
+	// maximum number of exported clients, zero means unlimited
+	Limit int `toml:"limit"`
+	// optional allowlist of client MAC addresses
+	Macs []string `toml:"macs"`
+	// optional regular expression for client MAC addresses
+	MacRegex string `toml:"mac_regex"`
 }
 
 // LoadConfig loads the configuration from a file
@@ -33,19 +96,116 @@ func LoadConfig(file string) (*Config, error) {
 		return nil, fmt.Errorf("loading config file %q failed: %w", file, err)
 	}
 
+	for i := range cfg.Controllers {
+		ctrl := &cfg.Controllers[i]
+		if err := ctrl.validate(); err != nil {
+			return nil, fmt.Errorf("controller %q: %w", ctrl.Alias, err)
+		}
+	}
+
+	for i := range cfg.Controllers {
+		if !cfg.Controllers[i].TLSTrustOnFirstUse {
+			continue
+		}
+		if cfg.TLSStateFile == "" {
+			return nil, fmt.Errorf("controller %q: tls_trust_on_first_use requires tls_state_file", cfg.Controllers[i].Alias)
+		}
+		if cfg.fingerprints == nil {
+			store, err := client.NewFingerprintStore(cfg.TLSStateFile)
+			if err != nil {
+				return nil, fmt.Errorf("loading TLS state failed: %w", err)
+			}
+			cfg.fingerprints = store
+		}
+	}
+
 	return &cfg, nil
 }
 
 // getClient builds a client
 func (cfg *Config) getClient(target string) (*client.Client, error) {
+	cfg.mu.Lock()
+	defer cfg.mu.Unlock()
+
+	if ctrl := cfg.find(target); ctrl != nil {
+		return ctrl.getClient(cfg.fingerprints)
+	}
+
+	return nil, nil
+}
+
+// find returns the controller matching the target by alias or host
+func (cfg *Config) find(target string) *Controller {
 	for i := range cfg.Controllers {
 		ctrl := &cfg.Controllers[i]
 		if target == ctrl.Alias || target == ctrl.Host {
-			return ctrl.getClient()
+			return ctrl
 		}
 	}
 
-	return nil, nil
+	return nil
+}
+
+// validate checks the settings and prepares the parsed values, so that
+// errors are reported at startup instead of on the first scrape
+func (ctrl *Controller) validate() error {
+	durations := map[string]time.Duration{
+		"idle_conn_timeout":     ctrl.IdleConnTimeout,
+		"connect_timeout":       ctrl.ConnectTimeout,
+		"tls_handshake_timeout": ctrl.TLSHandshakeTimeout,
+		"timeout":               ctrl.Timeout,
+		"retry_backoff":         ctrl.RetryBackoff,
+		"cache_ttl":             ctrl.CacheTTL,
+	}
+	for name, d := range durations {
+		if d < 0 {
+			return fmt.Errorf("%s must not be negative", name)
+		}
+	}
+
+	if ctrl.Retries < 0 {
+		return errors.New("retries must not be negative")
+	}
+	if ctrl.Retries > maxRetries {
+		slog.Warn("limiting retries", "controller", ctrl.Alias, "retries", ctrl.Retries, "limit", maxRetries)
+		ctrl.Retries = maxRetries
+	}
+
+	if ctrl.ProxyURL != "" {
+		proxyURL, err := client.ParseProxyURL(ctrl.ProxyURL)
+		if err != nil {
+			return fmt.Errorf("invalid proxy_url: %w", err)
+		}
+		ctrl.proxyURL = proxyURL
+	}
+
+	// a pinned fingerprint replaces the chain and hostname verification
+	if (ctrl.TLSFingerprintSHA256 != "" || ctrl.TLSTrustOnFirstUse) && (ctrl.TLSCAFile != "" || ctrl.TLSServerName != "") {
+		return errors.New("tls_ca_file and tls_server_name cannot be combined with tls_fingerprint_sha256 or tls_trust_on_first_use")
+	}
+
+	if ctrl.TLSCAFile != "" {
+		if _, err := client.LoadCAFile(ctrl.TLSCAFile); err != nil {
+			return fmt.Errorf("invalid tls_ca_file: %w", err)
+		}
+	}
+
+	// verification against the system roots used to be disabled
+	if ctrl.TLSCAFile == "" && ctrl.TLSFingerprintSHA256 == "" && !ctrl.TLSTrustOnFirstUse && !ctrl.TLSInsecure {
+		slog.Warn("no TLS settings for controller, verifying its certificate against the system roots",
+			"controller", ctrl.Alias,
+		)
+	}
+
+	if wc := ctrl.WirelessClients; wc != nil {
+		filter, err := wc.filter()
+		if err != nil {
+			return err
+		}
+		ctrl.filter = filter
+	}
+
+	return nil
 }
 
 // url build the URL
@@ -64,15 +224,66 @@ func (ctrl *Controller) url() *url.URL {
 	}
 }
 
-func (ctrl *Controller) getClient() (*client.Client, error) {
-	if ctrl.client == nil {
+// options builds the client options
+func (ctrl *Controller) options(fingerprints *client.FingerprintStore) client.Options {
+	opts := client.Options{
+		Name:            ctrl.Alias,
+		MaxIdleConns:    ctrl.MaxIdleConns,
+		MaxConnsPerHost: ctrl.MaxConnsPerHost,
+		IdleConnTimeout: ctrl.IdleConnTimeout,
+		TLSCAFile:       ctrl.TLSCAFile,
+		TLSServerName:   ctrl.TLSServerName,
+		TLSFingerprint:  ctrl.TLSFingerprintSHA256,
+		TLSInsecure:     ctrl.TLSInsecure,
 
-		c, err := client.NewClient(ctrl.url())
+		ConnectTimeout:      ctrl.ConnectTimeout,
+		TLSHandshakeTimeout: ctrl.TLSHandshakeTimeout,
+		Timeout:             ctrl.Timeout,
+		Retries:             ctrl.Retries,
+		RetryBackoff:        ctrl.RetryBackoff,
+		CacheTTL:            ctrl.CacheTTL,
+		RecordDir:           ctrl.RecordDir,
+		RecordRedact:        ctrl.RecordRedact,
+		ReplayDir:           ctrl.ReplayDir,
+		ProxyURL:            ctrl.proxyURL,
+	}
+
+	if ctrl.TLSTrustOnFirstUse {
+		opts.TLSStore = fingerprints
+	}
+
+	return opts
+}
+
+func (ctrl *Controller) getClient(fingerprints *client.FingerprintStore) (*client.Client, error) {
+	if ctrl.client == nil {
+		c, err := client.NewClient(ctrl.url(), ctrl.options(fingerprints))
 		if err != nil {
 			return nil, err
 		}
+
+		c.ExpectedConfigHash = ctrl.ExpectedConfigHash
+		c.WirelessClients = ctrl.filter
 		ctrl.client = c
 	}
 
 	return ctrl.client, nil
 }
+
+// filter builds the client filter
+func (wc *WirelessClients) filter() (*types.ClientFilter, error) {
+	filter := &types.ClientFilter{
+		Limit: wc.Limit,
+		Macs:  wc.Macs,
+	}
+
+	if wc.MacRegex != "" {
+		re, err := regexp.Compile(wc.MacRegex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid mac_regex: %w", err)
+		}
+		filter.MacRegex = re
+	}
+
+	return filter, nil
+}
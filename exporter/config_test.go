@@ -1,7 +1,10 @@
 package exporter
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -23,4 +26,67 @@ func TestConfig(t *testing.T) {
 	assert.Equal("192.168.10.1", controller.Host)
 	assert.Equal("admin", controller.Password)
 	assert.EqualValues(8443, controller.Port)
+	assert.Equal(2, controller.MaxIdleConns)
+	assert.Equal(4, controller.MaxConnsPerHost)
+	assert.Equal(time.Minute, controller.IdleConnTimeout)
+	assert.Equal(5*time.Second, controller.ConnectTimeout)
+	assert.Equal(2, controller.Retries)
+	assert.Equal(500*time.Millisecond, controller.RetryBackoff)
+	assert.Equal(10*time.Second, controller.CacheTTL)
+}
+
+// writeConfig writes a config file with a single controller
+func writeConfig(t *testing.T, settings string) string {
+	t.Helper()
+
+	file := filepath.Join(t.TempDir(), "config.toml")
+	data := "[[eoc-controller]]\nalias = \"test\"\nhost = \"192.0.2.1\"\n" + settings
+	require.NoError(t, os.WriteFile(file, []byte(data), 0o600))
+
+	return file
+}
+
+func TestConfigInvalid(t *testing.T) {
+	tests := map[string]string{
+		"mac_regex":     "[eoc-controller.wireless_clients]\nmac_regex = \"(\"\n",
+		"tls_ca_file":   "tls_ca_file = \"/nonexistent/ca.pem\"\n",
+		"retries":       "retries = -1\n",
+		"retry_backoff": "retry_backoff = \"-1s\"\n",
+		"proxy_url":     "proxy_url = \"ftp://proxy\"\n",
+
+		"tls_fingerprint_sha256": "tls_fingerprint_sha256 = \"ab:cd\"\ntls_server_name = \"controller\"\n",
+		"tls_trust_on_first_use": "tls_trust_on_first_use = true\ntls_ca_file = \"ca.pem\"\n",
+	}
+
+	for name, settings := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := LoadConfig(writeConfig(t, settings))
+			assert.ErrorContains(t, err, name)
+		})
+	}
+}
+
+func TestConfigWirelessClients(t *testing.T) {
+	config, err := LoadConfig(writeConfig(t, "[eoc-controller.wireless_clients]\nlimit = 5\nmac_regex = \"^aa:\"\n"))
+	require.NoError(t, err)
+
+	filter := config.Controllers[0].filter
+	require.NotNil(t, filter)
+	assert.Equal(t, 5, filter.Limit)
+	assert.True(t, filter.Match("AA:BB:CC:DD:EE:FF"))
+}
+
+func TestConfigFind(t *testing.T) {
+	config, err := LoadConfig("../config.example.toml")
+	require.NoError(t, err)
+
+	assert.Equal(t, "my-controller", config.find("my-controller").Alias)
+	assert.Equal(t, "my-controller", config.find("192.168.10.1").Alias)
+	assert.Nil(t, config.find("unknown"))
+}
+
+func TestConfigRetriesLimit(t *testing.T) {
+	config, err := LoadConfig(writeConfig(t, "retries = 1000\n"))
+	require.NoError(t, err)
+	assert.Equal(t, maxRetries, config.Controllers[0].Retries)
 }
@@ -3,14 +3,17 @@ package exporter
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"io"
 	"log/slog"
 	"net/http"
 	"text/template"
 
 	"github.com/digineo/triax-eoc-exporter/client"
+	"github.com/digineo/triax-eoc-exporter/types"
 	"github.com/julienschmidt/httprouter"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	_ "github.com/digineo/triax-eoc-exporter/backend/v3"
@@ -26,10 +29,13 @@ func (cfg *Config) Start(listenAddress, version, date string) {
 		})
 	})
 
+	router.Handler(http.MethodGet, "/metrics", promhttp.HandlerFor(selfRegistry(), promhttp.HandlerOpts{}))
 	router.GET("/controllers", cfg.listControllersHandler)
 	router.GET("/controllers/:target/metrics", cfg.targetMiddleware(cfg.metricsHandler))
+	router.GET("/controllers/:target/endpoints", cfg.targetMiddleware(cfg.endpointsHandler))
 	router.GET("/controllers/:target/config", cfg.targetMiddleware(cfg.getConfigHandler))
 	router.POST("/controllers/:target/config", cfg.targetMiddleware(cfg.updateConfigHandler))
+	router.GET("/controllers/:target/system", cfg.targetMiddleware(cfg.systemHandler))
 
 	slog.Info("Starting exporter", "listenAddress", listenAddress, "version", version, "builtDate", date)
 	slog.Info("Server stopped", "reason", http.ListenAndServe(listenAddress, router))
@@ -67,7 +73,7 @@ func (cfg *Config) listControllersHandler(w http.ResponseWriter, r *http.Request
 	json.NewEncoder(w).Encode(&result)
 }
 
-func (cfg *Config) metricsHandler(client *client.Client, w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+func (cfg *Config) metricsHandler(client *client.Client, w http.ResponseWriter, r *http.Request, params httprouter.Params) {
 	reg := prometheus.NewRegistry()
 	reg.MustRegister(&triaxCollector{
 		client: client,
@@ -77,6 +83,30 @@ func (cfg *Config) metricsHandler(client *client.Client, w http.ResponseWriter,
 	h.ServeHTTP(w, r)
 }
 
+// selfRegistry builds the registry for metrics about the exporter itself
+func selfRegistry() *prometheus.Registry {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		scrapeDuration,
+	)
+	client.MustRegisterMetrics(reg)
+	return reg
+}
+
+// handler for listing the endpoint inventory
+func (cfg *Config) endpointsHandler(client *client.Client, w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	endpoints, err := client.Endpoints(r.Context())
+	if err != nil {
+		backendError(w, err)
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(&endpoints)
+}
+
 // handler for updating configs
 func (cfg *Config) updateConfigHandler(client *client.Client, w http.ResponseWriter, r *http.Request, params httprouter.Params) {
 	defer r.Body.Close()
@@ -92,7 +122,7 @@ func (cfg *Config) updateConfigHandler(client *client.Client, w http.ResponseWri
 
 	err = client.SetConfig(r.Context(), jsonBody)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadGateway)
+		backendError(w, err)
 		return
 	}
 
@@ -104,13 +134,35 @@ func (cfg *Config) getConfigHandler(client *client.Client, w http.ResponseWriter
 	config, err := client.GetConfig(r.Context())
 
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadGateway)
+		backendError(w, err)
 		return
 	}
 
 	io.Copy(w, bytes.NewReader(config))
 }
 
+// handler for getting controller infos
+func (cfg *Config) systemHandler(client *client.Client, w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	info, err := client.SystemInfo(r.Context())
+	if err != nil {
+		backendError(w, err)
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(info)
+}
+
+// backendError writes the error of a backend operation
+func backendError(w http.ResponseWriter, err error) {
+	status := http.StatusBadGateway
+	if errors.Is(err, types.ErrNotSupported) {
+		status = http.StatusNotImplemented
+	}
+
+	http.Error(w, err.Error(), status)
+}
+
 type indexVariables struct {
 	Controllers []Controller
 	Version     string
@@ -130,6 +182,8 @@ var tmpl = template.Must(template.New("index").Option("missingkey=error").Parse(
 		Built at: {{.Date}}
 	</p>
 
+	<p><a href="/metrics">Exporter metrics</a></p>
+
 	<h2>Controllers</h2>
 	<p><a href="/controllers">List as JSON</a></p>
 	<dl>
@@ -137,6 +191,8 @@ var tmpl = template.Must(template.New("index").Option("missingkey=error").Parse(
 		<dt>{{.Alias}}</dt>
 		<dd>
 			<a href="/controllers/{{.Alias}}/metrics">Metrics</a>,
+			<a href="/controllers/{{.Alias}}/system">System</a>,
+			<a href="/controllers/{{.Alias}}/endpoints">Endpoints</a>,
 			<a href="/controllers/{{.Alias}}/config">Config</a>
 		</dd>
 	{{end}}
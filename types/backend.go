@@ -2,10 +2,42 @@ package types
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/prometheus/client_golang/prometheus"
 )
 
+// Backend is the version-independent API of a controller. Operations a
+// backend cannot provide return ErrNotSupported.
 type Backend interface {
+	// Collect sends the metrics of the controller and its endpoints.
 	Collect(context.Context, chan<- prometheus.Metric) error
+
+	// GetConfig fetches the configuration of the controller.
+	GetConfig(context.Context) (json.RawMessage, error)
+
+	// SetConfig replaces the configuration of the controller.
+	SetConfig(context.Context, json.RawMessage) error
+
+	// Endpoints lists the endpoints of the controller.
+	Endpoints(context.Context) ([]Endpoint, error)
+
+	// SystemInfo fetches infos about the controller itself.
+	SystemInfo(context.Context) (*SystemInfo, error)
+}
+
+// FirmwareProber is implemented by backends able to detect the firmware
+// version of the controller. It is used for selecting the backend.
+type FirmwareProber interface {
+	Firmware(context.Context) (string, error)
+}
+
+// SystemInfo describes the controller.
+type SystemInfo struct {
+	Serial   string `json:"serial"`
+	Mac      string `json:"mac"`
+	Model    string `json:"model"`
+	Name     string `json:"name"`
+	Firmware string `json:"firmware"`
+	Uptime   int    `json:"uptime"`
 }
@@ -0,0 +1,73 @@
+package types
+
+import (
+	"slices"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Shorthands for the value types in the metric catalog.
+const (
+	Counter = prometheus.CounterValue
+	Gauge   = prometheus.GaugeValue
+)
+
+// Collectors owning the metrics in the catalog.
+const (
+	// metrics emitted by the exporter itself
+	CollectorExporter = "exporter"
+	// controller metrics emitted by a backend
+	CollectorController = "controller"
+	// endpoint metrics emitted by a backend
+	CollectorEndpoint = "endpoint"
+	// opt-in per-client metrics emitted by a backend
+	CollectorWirelessClients = "wireless_clients"
+)
+
+// CatalogEntry describes a metric known to the exporter.
+type CatalogEntry struct {
+	Desc      *prometheus.Desc
+	Type      prometheus.ValueType
+	Collector string
+}
+
+var catalog []CatalogEntry
+
+func register(collector string, typ prometheus.ValueType, desc *prometheus.Desc) *prometheus.Desc {
+	catalog = append(catalog, CatalogEntry{
+		Desc:      desc,
+		Type:      typ,
+		Collector: collector,
+	})
+	return desc
+}
+
+// Catalog returns all registered metrics owned by one of the given
+// collectors. Without any collector, all metrics are returned.
+func Catalog(collectors ...string) []CatalogEntry {
+	result := make([]CatalogEntry, 0, len(catalog))
+	for _, entry := range catalog {
+		if len(collectors) == 0 || slices.Contains(collectors, entry.Collector) {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+// Lookup finds the catalog entry for a descriptor.
+func Lookup(desc *prometheus.Desc) (CatalogEntry, bool) {
+	for _, entry := range catalog {
+		if entry.Desc == desc {
+			return entry, true
+		}
+	}
+	return CatalogEntry{}, false
+}
+
+// Describe sends the descriptors of all registered metrics owned by one
+// of the given collectors.
+func Describe(ch chan<- *prometheus.Desc, collectors ...string) {
+	for _, entry := range Catalog(collectors...) {
+		ch <- entry.Desc
+	}
+}
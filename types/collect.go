@@ -3,48 +3,173 @@ package types
 import "github.com/prometheus/client_golang/prometheus"
 
 var (
-	CtrlUp               = CtrlDesc("up", "indicator whether controller is reachable")
-	CtrlUptime           = CtrlDesc("uptime", "uptime of controller in seconds")
-	CtrlInfo             = CtrlDesc("info", "controller infos about the installed software", "serial", "eth_mac", "version")
-	CtrlLoad             = CtrlDesc("load", "current system load of controller")
-	CtrlMemoryTotal      = CtrlDesc("mem_total", "total system memory of controller in bytes")
-	CtrlMemoryFree       = CtrlDesc("mem_free", "free system memory of controller in bytes")
-	CtrlMemoryBuffered   = CtrlDesc("mem_buffered", "buffered system memory of controller in bytes")
-	CtrlMemoryShared     = CtrlDesc("mem_shared", "shared system memory of controller in bytes")
-	CtrlGhnNumOnline     = CtrlDesc("ghn_endpoints_online", "number of endponts online for a G.HN port", "port")
-	CtrlGhnNumRegistered = CtrlDesc("ghn_endpoints_registered", "number of endponts registered for a G.HN port", "port")
-
-	NodeLabel   = []string{"name"}
-	NodeInfo    = NodeDesc("info", "node infos", "serial", "mac", "model")
-	NodeStatus  = NodeDesc("status", "current endpoint status")
-	NodeUptime  = NodeDesc("uptime", "uptime of endpoint in seconds")
-	NodeOffline = NodeDesc("offline_since", "offline since unix timestamp")
-	NodeLoad    = NodeDesc("load", "current system load of endpoint")
-	NodeGhnPort = NodeDesc("ghn_port", "G.HN port number", "ghn_mac")
-	NodeClients = NodeDesc("clients", "number of connected WLAN clients", "band")
+	CtrlUp               = CtrlDesc(CollectorExporter, Gauge, "up", "indicator whether controller is reachable")
+	CtrlDataAge          = CtrlDesc(CollectorExporter, Gauge, "data_age_seconds", "age of the served controller data in seconds")
+	CtrlScrapeErrors     = CtrlDesc(CollectorExporter, Counter, "scrape_errors_total", "number of failed scrapes by reason", "reason")
+	CtrlLastError        = CtrlDesc(CollectorExporter, Gauge, "last_scrape_error_timestamp_seconds", "unix timestamp of the last failed scrape", "reason", "message")
+	CtrlBackendInfo      = CtrlDesc(CollectorExporter, Gauge, "backend_info", "backend selected for the controller and detected firmware", "backend", "firmware", "supported")
+	CtrlUptime           = CtrlDesc(CollectorController, Counter, "uptime", "uptime of controller in seconds")
+	CtrlInfo             = CtrlDesc(CollectorController, Gauge, "info", "controller infos about the installed software", "serial", "eth_mac", "version")
+	CtrlLoad             = CtrlDesc(CollectorController, Gauge, "load", "current CPU usage of controller in percent")
+	CtrlCPUUsage         = CtrlDesc(CollectorController, Gauge, "cpu_usage", "average CPU usage of controller in percent", "interval")
+	CtrlProcesses        = CtrlDesc(CollectorController, Gauge, "processes", "number of processes running on the controller")
+	CtrlImagesValid      = CtrlDesc(CollectorController, Gauge, "images_valid", "indicator whether the firmware images of the controller are valid")
+	CtrlClockOffset      = CtrlDesc(CollectorController, Gauge, "clock_offset_seconds", "difference between controller clock and exporter clock in seconds")
+	CtrlMemoryUsage      = CtrlDesc(CollectorController, Gauge, "mem_usage", "system memory usage of controller in percent")
+	CtrlMemoryTotal      = CtrlDesc(CollectorController, Gauge, "mem_total", "total system memory of controller in bytes")
+	CtrlMemoryFree       = CtrlDesc(CollectorController, Gauge, "mem_free", "free system memory of controller in bytes")
+	CtrlGhnNumOnline     = CtrlDesc(CollectorController, Gauge, "ghn_endpoints_online", "number of endponts online for a G.HN port", "port")
+	CtrlGhnNumRegistered = CtrlDesc(CollectorController, Gauge, "ghn_endpoints_registered", "number of endponts registered for a G.HN port", "port")
+
+	CtrlGhnLabel          = []string{"port"}
+	CtrlGhnInfo           = CtrlDesc(CollectorController, Gauge, "ghn_info", "G.HN modem infos", "port", "name", "firmware", "mac", "domain_id", "domain_name", "domain_mode", "master")
+	CtrlGhnResetCause     = CtrlDesc(CollectorController, Gauge, "ghn_reset_cause", "cause of the last G.HN modem reset", "port", "cause")
+	CtrlGhnCPUUsage       = CtrlDesc(CollectorController, Gauge, "ghn_cpu_usage", "CPU usage of G.HN modem in percent", CtrlGhnLabel...)
+	CtrlGhnMemUsage       = CtrlDesc(CollectorController, Gauge, "ghn_mem_usage", "memory usage of G.HN modem in percent", CtrlGhnLabel...)
+	CtrlGhnUptime         = CtrlDesc(CollectorController, Gauge, "ghn_uptime", "uptime of G.HN modem in seconds", CtrlGhnLabel...)
+	CtrlGhnSpeed          = CtrlDesc(CollectorController, Gauge, "ghn_speed", "speed of G.HN modem in Mbps", CtrlGhnLabel...)
+	CtrlGhnNoiseMin       = CtrlDesc(CollectorController, Gauge, "ghn_noise_min", "min noise level of G.HN modem in dBm", CtrlGhnLabel...)
+	CtrlGhnNoiseAvg       = CtrlDesc(CollectorController, Gauge, "ghn_noise_avg", "avg noise level of G.HN modem in dBm", CtrlGhnLabel...)
+	CtrlGhnNoiseMax       = CtrlDesc(CollectorController, Gauge, "ghn_noise_max", "max noise level of G.HN modem in dBm", CtrlGhnLabel...)
+	CtrlGhnNoiseAgc       = CtrlDesc(CollectorController, Gauge, "ghn_noise_agc", "automatic gain control of G.HN modem", CtrlGhnLabel...)
+	CtrlGhnBytes          = CtrlDesc(CollectorController, Counter, "ghn_bytes", "total bytes transmitted or received by G.HN modem", "port", "direction")
+	CtrlGhnPackets        = CtrlDesc(CollectorController, Counter, "ghn_packets", "total packets transmitted or received by G.HN modem", "port", "direction")
+	CtrlGhnErrors         = CtrlDesc(CollectorController, Counter, "ghn_errors", "total number of errors of G.HN modem", "port", "direction")
+	CtrlGhnDrops          = CtrlDesc(CollectorController, Counter, "ghn_drops", "total number of dropped packets of G.HN modem", "port", "direction")
+	CtrlGhnBlocks         = CtrlDesc(CollectorController, Counter, "ghn_blocks", "total blocks transmitted or received by G.HN modem", "port", "direction")
+	CtrlGhnTxBlocksResent = CtrlDesc(CollectorController, Counter, "ghn_tx_blocks_resent", "total blocks resent by G.HN modem", CtrlGhnLabel...)
+	CtrlGhnRxBlocksError  = CtrlDesc(CollectorController, Counter, "ghn_rx_blocks_error", "total erroneous blocks received by G.HN modem", CtrlGhnLabel...)
+	CtrlGhnLinkLost       = CtrlDesc(CollectorController, Counter, "ghn_link_lost", "number of G.HN link losses", CtrlGhnLabel...)
+	CtrlGhnRetxPercent    = CtrlDesc(CollectorController, Gauge, "ghn_retx_percent", "retransmission rate of G.HN modem in percent", CtrlGhnLabel...)
+	CtrlGhnFecPercent     = CtrlDesc(CollectorController, Gauge, "ghn_fec_percent", "forward error correction rate of G.HN modem in percent", CtrlGhnLabel...)
+
+	CtrlEthLabel     = []string{"port", "switch", "access"}
+	CtrlEthLink      = CtrlDesc(CollectorController, Gauge, "ethernet_link", "indicator whether the ethernet port has a link", CtrlEthLabel...)
+	CtrlEthAutoneg   = CtrlDesc(CollectorController, Gauge, "ethernet_autoneg", "indicator whether autonegotiation is enabled on the ethernet port", CtrlEthLabel...)
+	CtrlEthRate      = CtrlDesc(CollectorController, Gauge, "ethernet_rate", "current rate of the ethernet port", append(CtrlEthLabel, "direction")...)
+	CtrlEthBytes     = CtrlDesc(CollectorController, Counter, "ethernet_bytes", "total bytes transmitted or received by the ethernet port", append(CtrlEthLabel, "direction")...)
+	CtrlEthPackets   = CtrlDesc(CollectorController, Counter, "ethernet_packets", "total packets transmitted or received by the ethernet port", append(CtrlEthLabel, "direction")...)
+	CtrlEthErrors    = CtrlDesc(CollectorController, Counter, "ethernet_errors", "total number of errors of the ethernet port", append(CtrlEthLabel, "direction")...)
+	CtrlEthDrops     = CtrlDesc(CollectorController, Counter, "ethernet_drops", "total number of dropped packets of the ethernet port", append(CtrlEthLabel, "direction")...)
+	CtrlEthUnicast   = CtrlDesc(CollectorController, Counter, "ethernet_unicast", "total unicast packets transmitted or received by the ethernet port", append(CtrlEthLabel, "direction")...)
+	CtrlEthMulticast = CtrlDesc(CollectorController, Counter, "ethernet_multicast", "total multicast packets transmitted or received by the ethernet port", append(CtrlEthLabel, "direction")...)
+	CtrlEthBroadcast = CtrlDesc(CollectorController, Counter, "ethernet_broadcast", "total broadcast packets transmitted or received by the ethernet port", append(CtrlEthLabel, "direction")...)
+
+	CtrlHardwareInfo = CtrlDesc(CollectorController, Gauge, "hardware_info", "controller hardware infos", "board_no", "board_rev", "board_id", "type", "model", "name", "production_year", "production_week")
+	CtrlMacInfo      = CtrlDesc(CollectorController, Gauge, "mac_info", "MAC addresses of the controller interfaces", "interface", "mac")
+	CtrlFeature      = CtrlDesc(CollectorController, Gauge, "feature", "indicator whether the controller supports a feature", "feature")
+
+	CtrlConfigDrift = CtrlDesc(CollectorController, Gauge, "config_drift_endpoints", "number of endpoints whose config hash differs from the expected or most common one")
+
+	CtrlWirelessClientsSkipped = CtrlDesc(CollectorWirelessClients, Gauge, "wireless_clients_skipped", "number of wireless clients omitted due to the configured limit")
+
+	NodeLabel           = []string{"name"}
+	NodeInfo            = NodeDesc(CollectorEndpoint, Gauge, "info", "node infos", "serial", "mac", "model")
+	NodeStatus          = NodeDesc(CollectorEndpoint, Gauge, "status", "current endpoint status")
+	NodeOffline         = NodeDesc(CollectorEndpoint, Gauge, "offline_since", "offline since unix timestamp")
+	NodeUptime          = NodeDesc(CollectorEndpoint, Gauge, "uptime", "uptime of endpoint in seconds")
+	NodeGhnPort         = NodeDesc(CollectorEndpoint, Gauge, "ghn_port", "G.HN port number", "ghn_mac")
+	NodeClients         = NodeDesc(CollectorEndpoint, Gauge, "clients", "number of connected WLAN clients", "band")
+	NodeEthernetClients = NodeDesc(CollectorEndpoint, Gauge, "ethernet_clients", "number of devices attached to an ethernet port", "interface")
+
+	NodeState      = NodeDesc(CollectorEndpoint, Gauge, "state", "indicator whether the endpoint is in the given state", "state")
+	NodeStatusInfo = NodeDesc(CollectorEndpoint, Gauge, "status_info", "endpoint status as reported by the controller", "status", "message")
+
+	NodeConfigInfo = NodeDesc(CollectorEndpoint, Gauge, "config_info", "hash of the configuration applied to the endpoint", "config_hash")
+
+	NodeSoftwareInfo = NodeDesc(CollectorEndpoint, Gauge, "software_info", "endpoint infos about the installed software", "sw_version", "ghn_version", "hostname", "model", "description", "host_mac")
+	NodeNetworkInfo  = NodeDesc(CollectorEndpoint, Gauge, "network_info", "endpoint network addresses", "ipv4_address", "ipv4_prefix", "ipv6_address", "ipv6_prefix")
+	NodeAreaInfo     = NodeDesc(CollectorEndpoint, Gauge, "network_area_info", "endpoint network addresses per area", "network", "ipv4_address", "ipv4_prefix")
+
+	WifiInfo      = NodeDesc(CollectorEndpoint, Gauge, "wireless_info", "wireless radio infos", "band", "radio", "label", "mac", "channel_width")
+	WifiEnabled   = NodeDesc(CollectorEndpoint, Gauge, "wireless_enabled", "indicator whether the wireless radio is enabled", "band")
+	WifiChannel   = NodeDesc(CollectorEndpoint, Gauge, "wireless_channel", "current channel of the wireless radio", "band")
+	WifiFrequency = NodeDesc(CollectorEndpoint, Gauge, "wireless_frequency", "current frequency of the wireless radio in MHz", "band")
+	WifiTxPower   = NodeDesc(CollectorEndpoint, Gauge, "wireless_txpower", "transmit power of the wireless radio in dBm", "band")
+	WifiBitrate   = NodeDesc(CollectorEndpoint, Gauge, "wireless_bitrate", "bitrate of the wireless radio in Mbps", "band")
+
+	WifiClientLabel      = []string{"client_mac", "ssid", "band"}
+	WifiClientInfo       = NodeDesc(CollectorWirelessClients, Gauge, "wireless_client_info", "wireless client infos", append(WifiClientLabel, "hostname", "ipaddr", "radio", "protocol")...)
+	WifiClientSignal     = NodeDesc(CollectorWirelessClients, Gauge, "wireless_client_signal", "signal strength of wireless client in dBm", WifiClientLabel...)
+	WifiClientPer        = NodeDesc(CollectorWirelessClients, Gauge, "wireless_client_per", "packet error rate of wireless client in percent", WifiClientLabel...)
+	WifiClientUptime     = NodeDesc(CollectorWirelessClients, Gauge, "wireless_client_uptime", "connection time of wireless client in seconds", WifiClientLabel...)
+	WifiClientBitrate    = NodeDesc(CollectorWirelessClients, Gauge, "wireless_client_bitrate", "negotiated bitrate of wireless client", append(WifiClientLabel, "direction")...)
+	WifiClientThroughput = NodeDesc(CollectorWirelessClients, Gauge, "wireless_client_throughput", "current throughput of wireless client", append(WifiClientLabel, "direction")...)
+	WifiClientPackets    = NodeDesc(CollectorWirelessClients, Counter, "wireless_client_packets", "total packets transmitted or received by wireless client", append(WifiClientLabel, "direction")...)
+	WifiClientErrors     = NodeDesc(CollectorWirelessClients, Counter, "wireless_client_errors", "total number of errors of wireless client", append(WifiClientLabel, "direction")...)
 
 	LounterLabel   = []string{"interface", "direction"}
-	CounterBytes   = NodeDesc("interface_bytes", "total bytes transmitted or received", LounterLabel...)
-	CounterPackets = NodeDesc("interface_packets", "total packets transmitted or received", LounterLabel...)
-	CounterErrors  = NodeDesc("interface_errors", "total number of errors", LounterLabel...)
+	CounterBytes   = NodeDesc(CollectorEndpoint, Counter, "interface_bytes", "total bytes transmitted or received", LounterLabel...)
+	CounterPackets = NodeDesc(CollectorEndpoint, Counter, "interface_packets", "total packets transmitted or received", LounterLabel...)
+	CounterErrors  = NodeDesc(CollectorEndpoint, Counter, "interface_errors", "total number of errors", LounterLabel...)
+
+	GhnLabel = []string{"ghn_port", "ghn_label"}
+	GhnRxbps = NodeDesc(CollectorEndpoint, Gauge, "ghn_rxbps", "negotiated RX rate in bps", GhnLabel...)
+	GhnTxbps = NodeDesc(CollectorEndpoint, Gauge, "ghn_txbps", "negotiated TX rate in bps", GhnLabel...)
+
+	// values of the G.hn link, measured by the G.hn interface ghn_port on
+	// the given side of the link
+	GhnLinkLabel  = []string{"side", "ghn_port", "ghn_label"}
+	GhnSnrMin     = NodeDesc(CollectorEndpoint, Gauge, "ghn_snr_min", "min SNR level in dBm", GhnLinkLabel...)
+	GhnSnrAvg     = NodeDesc(CollectorEndpoint, Gauge, "ghn_snr_avg", "avg SNR level in dBm", GhnLinkLabel...)
+	GhnSnrMax     = NodeDesc(CollectorEndpoint, Gauge, "ghn_snr_max", "max SNR level in dBm", GhnLinkLabel...)
+	GhnWireLength = NodeDesc(CollectorEndpoint, Gauge, "ghn_wire_length", "wire length in meters", GhnLinkLabel...)
+
+	GhnPowerMin       = NodeDesc(CollectorEndpoint, Gauge, "ghn_power_min", "min power level in dBm", GhnLinkLabel...)
+	GhnPowerAvg       = NodeDesc(CollectorEndpoint, Gauge, "ghn_power_avg", "avg power level in dBm", GhnLinkLabel...)
+	GhnPowerMax       = NodeDesc(CollectorEndpoint, Gauge, "ghn_power_max", "max power level in dBm", GhnLinkLabel...)
+	GhnPowerAgc       = NodeDesc(CollectorEndpoint, Gauge, "ghn_power_agc", "automatic gain control", GhnLinkLabel...)
+	GhnRxErrorPercent = NodeDesc(CollectorEndpoint, Gauge, "ghn_rx_error_percent", "RX error rate in percent", GhnLinkLabel...)
+	GhnRxAbortPercent = NodeDesc(CollectorEndpoint, Gauge, "ghn_rx_abort_percent", "RX abort rate in percent", GhnLinkLabel...)
+	GhnPhyRate        = NodeDesc(CollectorEndpoint, Gauge, "ghn_phy_rate", "PHY rate in Mbps", append(GhnLinkLabel, "direction")...)
+	GhnRxFrames       = NodeDesc(CollectorEndpoint, Counter, "ghn_rx_frames", "total number of received frames", GhnLinkLabel...)
+	GhnRxLPDUs        = NodeDesc(CollectorEndpoint, Counter, "ghn_rx_lpdus", "total number of received LPDUs", GhnLinkLabel...)
+
+	GhnStatus      = NodeDesc(CollectorEndpoint, Gauge, "ghn_status", "indicator whether the G.HN interface is up", GhnLabel...)
+	GhnNoiseMin    = NodeDesc(CollectorEndpoint, Gauge, "ghn_noise_min", "min noise level in dBm", GhnLabel...)
+	GhnNoiseAvg    = NodeDesc(CollectorEndpoint, Gauge, "ghn_noise_avg", "avg noise level in dBm", GhnLabel...)
+	GhnNoiseMax    = NodeDesc(CollectorEndpoint, Gauge, "ghn_noise_max", "max noise level in dBm", GhnLabel...)
+	GhnNoiseAgc    = NodeDesc(CollectorEndpoint, Gauge, "ghn_noise_agc", "automatic gain control of noise measurement", GhnLabel...)
+	GhnRetxPercent = NodeDesc(CollectorEndpoint, Gauge, "ghn_retx_percent", "retransmission rate in percent", GhnLabel...)
+	GhnFecPercent  = NodeDesc(CollectorEndpoint, Gauge, "ghn_fec_percent", "forward error correction rate in percent", GhnLabel...)
+	GhnSpeed       = NodeDesc(CollectorEndpoint, Gauge, "ghn_speed", "speed of G.HN interface in Mbps", GhnLabel...)
+	GhnCPUUsage    = NodeDesc(CollectorEndpoint, Gauge, "ghn_cpu_usage", "CPU usage of G.HN chip in percent", GhnLabel...)
+	GhnMemUsage    = NodeDesc(CollectorEndpoint, Gauge, "ghn_mem_usage", "memory usage of G.HN chip in percent", GhnLabel...)
+	GhnUptime      = NodeDesc(CollectorEndpoint, Gauge, "ghn_uptime", "uptime of G.HN interface in seconds", GhnLabel...)
+	GhnClients     = NodeDesc(CollectorEndpoint, Gauge, "ghn_clients", "number of clients behind the G.HN interface", GhnLabel...)
+	GhnResetCause  = NodeDesc(CollectorEndpoint, Gauge, "ghn_reset_cause", "cause of the last G.HN chip reset", append(GhnLabel, "cause")...)
 
-	GhnRxbps      = NodeDesc("ghn_rxbps", "negotiated RX rate in bps")
-	GhnTxbps      = NodeDesc("ghn_txbps", "negotiated TX rate in bps")
-	GhnSnrMin     = NodeDesc("ghn_snr_min", "min SNR level in dBm", "side")
-	GhnSnrAvg     = NodeDesc("ghn_snr_avg", "avg SNR level in dBm", "side")
-	GhnSnrMax     = NodeDesc("ghn_snr_max", "max SNR level in dBm", "side")
-	GhnWireLength = NodeDesc("ghn_wire_length", "wire length in meters")
+	// names of the endpoint states
+	EndpointStates = []EndpointState{
+		{1, "ok"},
+		{2, "configuring"},
+		{4, "updating"},
+		{8, "offline_responding"},
+		{9, "offline_detected"},
+		{10, "offline"},
+	}
 
 	SIDE_ENDPOINT   = "endpoint"
 	SIDE_CONTROLLER = "controller"
 )
 
-func CtrlDesc(name, help string, extraLabel ...string) *prometheus.Desc {
+type EndpointState struct {
+	Value int
+	Name  string
+}
+
+// IsOffline reports whether the numeric endpoint state is one of the
+// offline variants.
+func IsOffline(state int) bool {
+	return state >= 8 && state <= 10
+}
+
+func CtrlDesc(collector string, typ prometheus.ValueType, name, help string, extraLabel ...string) *prometheus.Desc {
 	fqdn := prometheus.BuildFQName("triax", "eoc_controller", name)
-	return prometheus.NewDesc(fqdn, help, extraLabel, nil)
+	return register(collector, typ, prometheus.NewDesc(fqdn, help, extraLabel, nil))
 }
 
-func NodeDesc(name, help string, extraLabel ...string) *prometheus.Desc {
+func NodeDesc(collector string, typ prometheus.ValueType, name, help string, extraLabel ...string) *prometheus.Desc {
 	fqdn := prometheus.BuildFQName("triax", "eoc_endpoint", name)
-	return prometheus.NewDesc(fqdn, help, append(NodeLabel, extraLabel...), nil)
+	return register(collector, typ, prometheus.NewDesc(fqdn, help, append(NodeLabel, extraLabel...), nil))
 }
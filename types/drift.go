@@ -0,0 +1,37 @@
+package types
+
+// ConfigDrift counts the config hashes differing from the expected hash.
+// Without an expected hash, the most common hash is taken as reference.
+// Empty hashes are ignored.
+func ConfigDrift(hashes []string, expected string) int {
+	if expected == "" {
+		expected = majority(hashes)
+	}
+
+	drift := 0
+	for _, hash := range hashes {
+		if hash != "" && hash != expected {
+			drift++
+		}
+	}
+	return drift
+}
+
+// majority returns the most common non-empty value. Ties are broken by
+// choosing the lexically smallest value.
+func majority(values []string) string {
+	count := make(map[string]int)
+	for _, v := range values {
+		if v != "" {
+			count[v]++
+		}
+	}
+
+	result := ""
+	for v, n := range count {
+		if n > count[result] || (n == count[result] && v < result) {
+			result = v
+		}
+	}
+	return result
+}
@@ -0,0 +1,29 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigDrift(t *testing.T) {
+	tests := []struct {
+		name     string
+		hashes   []string
+		expected string
+		drift    int
+	}{
+		{"empty", nil, "", 0},
+		{"consistent", []string{"a", "a", "a"}, "", 0},
+		{"majority", []string{"a", "b", "a", ""}, "", 1},
+		{"tie", []string{"b", "a"}, "", 1},
+		{"expected", []string{"a", "a", "b"}, "b", 2},
+		{"expected missing", []string{"a", "b"}, "c", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.drift, ConfigDrift(tt.hashes, tt.expected))
+		})
+	}
+}
@@ -1,8 +1,14 @@
 package types
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
+	"net/http"
 )
 
 type ErrInvalidEndpoint struct {
@@ -19,6 +25,16 @@ func (err *ErrInvalidEndpoint) Unwrap() error {
 
 var ErrMissingCredentials = errors.New("missing username/password")
 
+var ErrNoBackend = errors.New("no usable backend found")
+
+var ErrNotSupported = errors.New("not supported by backend")
+
+type ErrLoginFailed struct{ Message string }
+
+func (err *ErrLoginFailed) Error() string {
+	return fmt.Sprintf("login failed: %s", err.Message)
+}
+
 type GenericError struct{ Msg string }
 
 func (err *GenericError) Error() string {
@@ -36,3 +52,122 @@ type ErrUnexpectedStatus struct {
 func (err *ErrUnexpectedStatus) Error() string {
 	return fmt.Sprintf("unexpected status %d for %v %v: %s", err.Status, err.Method, err.URL, err.Body)
 }
+
+type ErrFingerprintMismatch struct {
+	Host     string
+	Expected string
+	Actual   string
+}
+
+func (err *ErrFingerprintMismatch) Error() string {
+	return fmt.Sprintf("certificate fingerprint of %s changed: expected %s, got %s", err.Host, err.Expected, err.Actual)
+}
+
+// Reasons for failed scrapes, see Classify.
+const (
+	ReasonDNS        = "dns"
+	ReasonConnect    = "connect"
+	ReasonTLS        = "tls"
+	ReasonTimeout    = "timeout"
+	ReasonHTTPStatus = "http_status"
+	ReasonAuth       = "auth"
+	ReasonBackend    = "backend_detection"
+	ReasonDecode     = "json_decode"
+	ReasonOther      = "other"
+)
+
+// Reasons lists all values returned by Classify.
+var Reasons = []string{
+	ReasonDNS,
+	ReasonConnect,
+	ReasonTLS,
+	ReasonTimeout,
+	ReasonHTTPStatus,
+	ReasonAuth,
+	ReasonBackend,
+	ReasonDecode,
+	ReasonOther,
+}
+
+// reasonMessages describe the reasons of failed scrapes.
+var reasonMessages = map[string]string{
+	ReasonDNS:        "resolving controller address failed",
+	ReasonConnect:    "connecting to controller failed",
+	ReasonTLS:        "TLS handshake or verification failed",
+	ReasonTimeout:    "request timed out",
+	ReasonHTTPStatus: "unexpected HTTP status",
+	ReasonAuth:       "authentication failed",
+	ReasonBackend:    "no usable backend found",
+	ReasonDecode:     "decoding response failed",
+	ReasonOther:      "scrape failed",
+}
+
+// Summary returns a short description of a failed scrape, suitable as
+// label value. Unlike the error itself, it contains neither response
+// bodies nor addresses, and takes only a bounded number of values.
+func Summary(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	reason := Classify(err)
+	if statusErr := (&ErrUnexpectedStatus{}); reason == ReasonHTTPStatus && errors.As(err, &statusErr) {
+		return fmt.Sprintf("unexpected HTTP status %d", statusErr.Status)
+	}
+
+	return reasonMessages[reason]
+}
+
+// Classify returns the reason of a failed scrape. For errors wrapping
+// several causes, like the errors of all backends returned with
+// ErrNoBackend, the first matching reason wins:
+//
+//  1. network errors (dns, tls, timeout, connect), as no backend could
+//     reach the controller
+//  2. failed logins and 401/403 responses (auth)
+//  3. ErrNoBackend (backend_detection), i.e. the controller answered but
+//     no backend understood it, like a 404 for the login path
+//  4. other responses (http_status, json_decode)
+func Classify(err error) string {
+	var (
+		dnsErr         *net.DNSError
+		opErr          *net.OpError
+		netErr         net.Error
+		fingerprintErr *ErrFingerprintMismatch
+		verifyErr      *tls.CertificateVerificationError
+		recordErr      tls.RecordHeaderError
+		authorityErr   x509.UnknownAuthorityError
+		hostnameErr    x509.HostnameError
+		invalidErr     x509.CertificateInvalidError
+		loginErr       *ErrLoginFailed
+		statusErr      *ErrUnexpectedStatus
+		syntaxErr      *json.SyntaxError
+		typeErr        *json.UnmarshalTypeError
+	)
+
+	switch {
+	case err == nil:
+		return ""
+	case errors.As(err, &dnsErr):
+		return ReasonDNS
+	case errors.As(err, &fingerprintErr), errors.As(err, &verifyErr), errors.As(err, &recordErr),
+		errors.As(err, &authorityErr), errors.As(err, &hostnameErr), errors.As(err, &invalidErr):
+		return ReasonTLS
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return ReasonTimeout
+	case errors.As(err, &opErr) && opErr.Op == "dial":
+		return ReasonConnect
+	case errors.As(err, &loginErr):
+		return ReasonAuth
+	case errors.As(err, &statusErr) && (statusErr.Status == http.StatusUnauthorized || statusErr.Status == http.StatusForbidden):
+		return ReasonAuth
+	case errors.Is(err, ErrNoBackend):
+		return ReasonBackend
+	case errors.As(err, &statusErr):
+		return ReasonHTTPStatus
+	case errors.As(err, &syntaxErr), errors.As(err, &typeErr):
+		return ReasonDecode
+	default:
+		return ReasonOther
+	}
+}
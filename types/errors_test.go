@@ -0,0 +1,80 @@
+package types
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassify(t *testing.T) {
+	requestFailed := func(err error) error {
+		return fmt.Errorf("request failed: %w", &url.Error{Op: "Get", URL: "https://192.0.2.1/", Err: err})
+	}
+	syntaxErr := json.Unmarshal([]byte("{"), &struct{}{})
+
+	tests := []struct {
+		err    error
+		reason string
+	}{
+		{nil, ""},
+		{requestFailed(&net.DNSError{Err: "no such host", Name: "ctrl"}), ReasonDNS},
+		{requestFailed(&net.OpError{Op: "dial", Err: errors.New("connection refused")}), ReasonConnect},
+		{requestFailed(context.DeadlineExceeded), ReasonTimeout},
+		{requestFailed(&ErrFingerprintMismatch{}), ReasonTLS},
+		{&ErrUnexpectedStatus{Status: 500}, ReasonHTTPStatus},
+		{&ErrUnexpectedStatus{Status: 401}, ReasonAuth},
+		{errors.Join(ErrNoBackend, &ErrLoginFailed{}), ReasonAuth},
+		{errors.Join(ErrNoBackend, errors.New("unknown")), ReasonBackend},
+		{fmt.Errorf("decoding response failed: %w", syntaxErr), ReasonDecode},
+		{errors.New("unknown"), ReasonOther},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.reason, Classify(tt.err), "%v", tt.err)
+	}
+}
+
+func TestClassifyBackendErrors(t *testing.T) {
+	// errors as returned by client.Try, wrapping the errors of all backends
+	try := func(errs ...error) error {
+		return errors.Join(append([]error{ErrNoBackend}, errs...)...)
+	}
+	notFound := &ErrUnexpectedStatus{Status: 404}
+	dnsErr := &net.DNSError{Err: "no such host", Name: "ctrl"}
+	syntaxErr := json.Unmarshal([]byte("{"), &struct{}{})
+
+	tests := []struct {
+		name   string
+		err    error
+		reason string
+	}{
+		{"no backends", try(), ReasonBackend},
+		{"unknown login path", try(notFound, notFound), ReasonBackend},
+		{"unexpected response", try(fmt.Errorf("decoding response failed: %w", syntaxErr)), ReasonBackend},
+		{"wrong password", try(notFound, &ErrLoginFailed{}), ReasonAuth},
+		{"unauthorized", try(&ErrUnexpectedStatus{Status: 401}), ReasonAuth},
+		{"unreachable", try(dnsErr, &ErrLoginFailed{}), ReasonDNS},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.reason, Classify(tt.err), tt.name)
+	}
+}
+
+func TestSummary(t *testing.T) {
+	assert.Empty(t, Summary(nil))
+	assert.Equal(t, "unexpected HTTP status 503", Summary(&ErrUnexpectedStatus{Status: 503, Body: []byte("secret\nbody")}))
+	assert.Equal(t, "authentication failed", Summary(errors.Join(ErrNoBackend, &ErrLoginFailed{Message: "wrong password"})))
+	assert.Equal(t, "no usable backend found", Summary(errors.Join(ErrNoBackend, &ErrUnexpectedStatus{Status: 404})))
+	assert.Equal(t, "scrape failed", Summary(errors.New("some\nmulti-line error")))
+
+	for _, reason := range Reasons {
+		assert.NotEmpty(t, reasonMessages[reason], reason)
+	}
+}
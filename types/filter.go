@@ -0,0 +1,36 @@
+package types
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ClientFilter restricts the wireless clients exported as individual
+// series. A nil filter disables per-client metrics entirely.
+type ClientFilter struct {
+	// maximum number of clients per controller, zero means unlimited
+	Limit int
+
+	// allowed client MAC addresses
+	Macs []string
+
+	// allowed client MAC addresses as regular expression
+	MacRegex *regexp.Regexp
+}
+
+// Match reports whether a client with the given MAC address should be
+// exported. Without an allowlist and regex, every client matches.
+func (f *ClientFilter) Match(mac string) bool {
+	if len(f.Macs) == 0 && f.MacRegex == nil {
+		return true
+	}
+
+	mac = strings.ToLower(mac)
+	for _, allowed := range f.Macs {
+		if strings.ToLower(allowed) == mac {
+			return true
+		}
+	}
+
+	return f.MacRegex != nil && f.MacRegex.MatchString(mac)
+}
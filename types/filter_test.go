@@ -0,0 +1,31 @@
+package types
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientFilter(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter ClientFilter
+		mac    string
+		want   bool
+	}{
+		{"no restriction", ClientFilter{}, "aa:bb:cc:dd:ee:ff", true},
+		{"allowlist", ClientFilter{Macs: []string{"AA:BB:CC:DD:EE:FF"}}, "aa:bb:cc:dd:ee:ff", true},
+		{"not in allowlist", ClientFilter{Macs: []string{"aa:bb:cc:dd:ee:00"}}, "aa:bb:cc:dd:ee:ff", false},
+		{"regex", ClientFilter{MacRegex: regexp.MustCompile("^aa:bb:")}, "AA:BB:CC:DD:EE:FF", true},
+		{"not matching regex", ClientFilter{MacRegex: regexp.MustCompile("^00:11:")}, "aa:bb:cc:dd:ee:ff", false},
+		{"allowlist or regex", ClientFilter{Macs: []string{"aa:bb:cc:dd:ee:ff"}, MacRegex: regexp.MustCompile("^00:11:")}, "00:11:22:33:44:55", true},
+		{"limit only", ClientFilter{Limit: 1}, "aa:bb:cc:dd:ee:ff", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.filter.Match(tt.mac))
+		})
+	}
+}
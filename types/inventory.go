@@ -0,0 +1,19 @@
+package types
+
+// Endpoint is an entry in the endpoint inventory.
+type Endpoint struct {
+	Name            string           `json:"name"`
+	Mac             string           `json:"mac"`
+	Serial          string           `json:"serial"`
+	Model           string           `json:"model"`
+	State           int              `json:"state"`
+	EthernetClients []EthernetClient `json:"ethernet_clients"`
+}
+
+// EthernetClient is a device attached to an ethernet port of an endpoint.
+type EthernetClient struct {
+	Interface string `json:"interface"`
+	Mac       string `json:"mac"`
+	Ipaddr    string `json:"ipaddr"`
+	Hostname  string `json:"hostname"`
+}
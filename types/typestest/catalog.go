@@ -0,0 +1,162 @@
+// Package typestest provides helpers for testing backends against the
+// metric catalog.
+package typestest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/digineo/triax-eoc-exporter/types"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// Collect runs the backend's Collect method and returns all emitted metrics.
+func Collect(t testing.TB, backend types.Backend) []prometheus.Metric {
+	t.Helper()
+
+	ch := make(chan prometheus.Metric)
+	done := make(chan error, 1)
+	go func() {
+		done <- backend.Collect(context.Background(), ch)
+		close(ch)
+	}()
+
+	var metrics []prometheus.Metric
+	for m := range ch {
+		metrics = append(metrics, m)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+
+	return metrics
+}
+
+// Value returns the value of the metric with the given descriptor and
+// label values. It fails the test if there is no such metric.
+func Value(t testing.TB, metrics []prometheus.Metric, desc *prometheus.Desc, labels ...string) float64 {
+	t.Helper()
+
+	// build a metric with the expected labels, sorted like in the results
+	want := dto.Metric{}
+	if err := prometheus.MustNewConstMetric(desc, prometheus.UntypedValue, 0, labels...).Write(&want); err != nil {
+		t.Fatalf("writing metric failed: %v", err)
+	}
+
+	for _, m := range metrics {
+		if m.Desc() != desc {
+			continue
+		}
+
+		pb := dto.Metric{}
+		if err := m.Write(&pb); err != nil {
+			t.Fatalf("writing metric failed: %v", err)
+		}
+		if !sameLabels(pb.Label, want.Label) {
+			continue
+		}
+
+		switch {
+		case pb.Counter != nil:
+			return pb.Counter.GetValue()
+		case pb.Gauge != nil:
+			return pb.Gauge.GetValue()
+		}
+	}
+
+	t.Fatalf("metric not found: %v %v", desc, labels)
+	return 0
+}
+
+func sameLabels(a, b []*dto.LabelPair) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].GetName() != b[i].GetName() || a[i].GetValue() != b[i].GetValue() {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckCatalog fails the test when a metric is not declared in the catalog
+// for one of the given collectors, has a different value type than
+// declared, or when a declared metric is never emitted.
+func CheckCatalog(t testing.TB, metrics []prometheus.Metric, collectors ...string) {
+	t.Helper()
+
+	emitted := make(map[*prometheus.Desc]bool)
+	for _, m := range metrics {
+		desc := m.Desc()
+		entry, ok := types.Lookup(desc)
+		if !ok {
+			t.Errorf("metric not declared in catalog: %v", desc)
+			continue
+		}
+
+		declared := false
+		for _, c := range collectors {
+			declared = declared || entry.Collector == c
+		}
+		if !declared {
+			t.Errorf("metric owned by unexpected collector %q: %v", entry.Collector, desc)
+		}
+
+		if typ := valueType(t, m); typ != entry.Type {
+			t.Errorf("metric has type %v, declared as %v: %v", typ, entry.Type, desc)
+		}
+
+		emitted[desc] = true
+	}
+
+	for _, entry := range types.Catalog(collectors...) {
+		if !emitted[entry.Desc] {
+			t.Errorf("metric declared but never emitted: %v", entry.Desc)
+		}
+	}
+
+	// let a pedantic registry check for duplicates and label consistency
+	reg := prometheus.NewPedanticRegistry()
+	if err := reg.Register(&replay{metrics, collectors}); err != nil {
+		t.Fatalf("registering metrics failed: %v", err)
+	}
+	if _, err := reg.Gather(); err != nil {
+		t.Errorf("gathering metrics failed: %v", err)
+	}
+}
+
+func valueType(t testing.TB, m prometheus.Metric) prometheus.ValueType {
+	t.Helper()
+
+	pb := dto.Metric{}
+	if err := m.Write(&pb); err != nil {
+		t.Fatalf("writing metric failed: %v", err)
+	}
+
+	switch {
+	case pb.Counter != nil:
+		return prometheus.CounterValue
+	case pb.Gauge != nil:
+		return prometheus.GaugeValue
+	default:
+		return prometheus.UntypedValue
+	}
+}
+
+// replay is a collector for already collected metrics.
+type replay struct {
+	metrics    []prometheus.Metric
+	collectors []string
+}
+
+func (r *replay) Describe(ch chan<- *prometheus.Desc) {
+	types.Describe(ch, r.collectors...)
+}
+
+func (r *replay) Collect(ch chan<- prometheus.Metric) {
+	for _, m := range r.metrics {
+		ch <- m
+	}
+}
@@ -0,0 +1,64 @@
+package types
+
+import (
+	"strconv"
+	"strings"
+)
+
+// VersionRange is a range of controller firmware versions. Min is
+// inclusive, Max is exclusive. Empty bounds are unlimited.
+type VersionRange struct {
+	Min string
+	Max string
+}
+
+// Contains reports whether the version is within the range. Unknown
+// (empty) versions are never contained.
+func (r VersionRange) Contains(version string) bool {
+	if version == "" {
+		return false
+	}
+
+	return (r.Min == "" || CompareVersions(version, r.Min) >= 0) &&
+		(r.Max == "" || CompareVersions(version, r.Max) < 0)
+}
+
+func (r VersionRange) String() string {
+	return "[" + r.Min + ", " + r.Max + ")"
+}
+
+// CompareVersions compares two dotted version strings numerically, like
+// "3.4.7" and "3.10". Missing components are treated as zero, anything
+// after the numeric part of a component (like "-rc1") is ignored.
+// The result is -1, 0 or +1.
+func CompareVersions(a, b string) int {
+	as := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	bs := strings.Split(strings.TrimPrefix(b, "v"), ".")
+
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		x, y := versionComponent(as, i), versionComponent(bs, i)
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+
+	return 0
+}
+
+func versionComponent(components []string, i int) int {
+	if i >= len(components) {
+		return 0
+	}
+
+	s := components[i]
+	end := 0
+	for end < len(s) && s[end] >= '0' && s[end] <= '9' {
+		end++
+	}
+
+	n, _ := strconv.Atoi(s[:end])
+	return n
+}
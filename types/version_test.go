@@ -0,0 +1,37 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"3.4.7", "3.4.7", 0},
+		{"3.4", "3.4.0", 0},
+		{"3.4.7", "3.10", -1},
+		{"4.0", "3.99.1", 1},
+		{"v3.1", "3.1", 0},
+		{"3.2-rc1", "3.2", 0},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, CompareVersions(tt.a, tt.b), "%s <=> %s", tt.a, tt.b)
+	}
+}
+
+func TestVersionRange(t *testing.T) {
+	r := VersionRange{Min: "3", Max: "4"}
+
+	assert.True(t, r.Contains("3.0"))
+	assert.True(t, r.Contains("3.4.7"))
+	assert.False(t, r.Contains("2.9.9"))
+	assert.False(t, r.Contains("4.0.1"))
+	assert.False(t, r.Contains(""))
+
+	assert.True(t, VersionRange{}.Contains("1.0"))
+}